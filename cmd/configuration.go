@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 	"github.com/grafana/k8s-manifest-tail/internal/config"
+	"github.com/grafana/k8s-manifest-tail/internal/discovery"
+	"github.com/grafana/k8s-manifest-tail/internal/kube"
 	"github.com/spf13/cobra"
 	"strings"
 )
@@ -11,9 +13,19 @@ var Configuration *config.Config
 
 // LoadConfiguration reads the config file and applies flag overrides.
 func LoadConfiguration(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load(configPath)
+	cfg, err := buildConfiguration()
+	if cfg != nil {
+		Configuration = cfg
+	}
+	return err
+}
+
+// buildConfiguration reads the config file, applies defaults and flag overrides, and validates the result. The
+// configuration is returned alongside validation errors so callers can still inspect it.
+func buildConfiguration() (*config.Config, error) {
+	cfg, err := config.Load(configFiles()...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if kubeconfigOverride != "" {
@@ -35,9 +47,9 @@ func LoadConfiguration(cmd *cobra.Command, args []string) error {
 	if outputFormatOverride != "" {
 		cfg.Output.Format = config.OutputFormat(strings.ToLower(outputFormatOverride))
 	}
-	err = validateOutputFormat(cfg.Output.Format)
+	err = cfg.Output.Format.Validate()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if refreshIntervalOverride != "" {
@@ -52,21 +64,24 @@ func LoadConfiguration(cmd *cobra.Command, args []string) error {
 		cfg.ExcludeNamespaces = excludeNamespacesOverride
 	}
 
+	if namespaceSelectorOverride != "" {
+		cfg.NamespaceSelector = namespaceSelectorOverride
+	}
+
 	if len(cfg.Objects) == 0 {
-		return fmt.Errorf("no objects found in configuration file")
+		return nil, fmt.Errorf("no objects found in configuration file")
 	}
 
-	Configuration = cfg
-	return Configuration.Validate()
+	return cfg, cfg.Validate()
 }
 
-func validateOutputFormat(format config.OutputFormat) error {
-	switch format {
-	case config.OutputFormatYAML, config.OutputFormatJSON:
-		return nil
-	default:
-		return fmt.Errorf("invalid output format %q (expected yaml or json)", format)
+// expandConfiguration resolves wildcard object rules against the cluster's API discovery.
+func expandConfiguration(clients *kube.Clients, cfg *config.Config) (*config.Config, error) {
+	expanded, err := discovery.ExpandConfig(clients.Discovery, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("expand wildcard rules: %w", err)
 	}
+	return expanded, nil
 }
 
 // ResetConfiguration clears the cached configuration (useful for tests).
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
 )
 
 var describeCmd = &cobra.Command{
@@ -18,6 +20,24 @@ func init() {
 }
 
 func runDescribe(cmd *cobra.Command, args []string) error {
-	_, _ = fmt.Fprintln(cmd.OutOrStdout(), Configuration.Describe())
+	cfg := Configuration
+	if cfg.HasWildcardRules() {
+		expanded, err := describeExpandedConfiguration()
+		if err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Unable to expand wildcard rules, describing them as written: %v\n", err)
+		} else {
+			cfg = expanded
+		}
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), cfg.Describe())
 	return nil
 }
+
+// describeExpandedConfiguration connects to the cluster to resolve wildcard rules into the kinds they cover.
+func describeExpandedConfiguration() (*config.Config, error) {
+	clients, err := GetKubeProvider().Provide(Configuration)
+	if err != nil {
+		return nil, fmt.Errorf("create kubernetes clients: %w", err)
+	}
+	return expandConfiguration(clients, Configuration)
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+	"github.com/grafana/k8s-manifest-tail/internal/discovery"
+	"github.com/grafana/k8s-manifest-tail/internal/manifest"
+)
+
+var filterDiff bool
+
+var filterCmd = &cobra.Command{
+	Use:   "filter [file...]",
+	Short: "Filter manifests from files or stdin and print the result, without a cluster",
+	Long: `Reads manifests from the listed files, or from stdin when none is given or the file is "-". Files hold one or
+more YAML or JSON documents, and a document of kind List stands for its items. Each object goes through the filters
+and patches of the first rule that would collect it, judged by its apiVersion, kind, name, labels, and namespace, and
+is printed in the output format of that rule. Objects that no rule matches get the global filters. Filters that
+consult the cluster rely on what they cached earlier.`,
+	PreRunE:      LoadConfiguration,
+	RunE:         runFilter,
+	SilenceUsage: true,
+}
+
+func init() {
+	filterCmd.Flags().BoolVar(&filterDiff, "diff", false, "Print a unified diff of each object against the input instead of the result")
+	rootCmd.AddCommand(filterCmd)
+}
+
+func runFilter(cmd *cobra.Command, args []string) error {
+	objects, err := readManifests(cmd.InOrStdin(), args)
+	if err != nil {
+		return err
+	}
+
+	// The processor filters like the one GetManifestProcessor builds, but hands the result back instead of writing it.
+	processor := manifest.NewRuleFilters(previewProcessor{}, manifest.FilterEnvironment{})
+	defer func() { _ = processor.Close() }()
+
+	out := cmd.OutOrStdout()
+	// YAML documents are separated from the YAML document before them.
+	var previousFormat config.OutputFormat
+	for _, obj := range objects {
+		input := obj.DeepCopy()
+		rule := matchingRule(Configuration, obj)
+		format := Configuration.GetOutput(rule).Format
+		diff, err := processor.Process(rule, obj, Configuration)
+		if errors.Is(err, manifest.ErrSkipObject) {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Skipped %s: %v\n", describeObject(input), err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("filter %s: %w", describeObject(input), err)
+		}
+
+		var filtered []byte
+		if diff != nil {
+			if filtered, err = manifest.Serialize(diff.Current, format); err != nil {
+				return err
+			}
+		}
+		if filterDiff {
+			original, err := manifest.Serialize(input, format)
+			if err != nil {
+				return err
+			}
+			toLabel := describeObject(input) + " (filtered)"
+			if diff == nil {
+				toLabel = describeObject(input) + " (dropped)"
+			}
+			_, _ = io.WriteString(out, unifiedDiff(describeObject(input)+" (input)", toLabel, original, filtered))
+			continue
+		}
+		if diff == nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Dropped %s\n", describeObject(input))
+			continue
+		}
+		if format == config.OutputFormatYAML && previousFormat == config.OutputFormatYAML {
+			_, _ = io.WriteString(out, "---\n")
+		}
+		_, _ = out.Write(filtered)
+		previousFormat = format
+	}
+	return nil
+}
+
+// readManifests decodes every object in the supplied files, or in stdin, replacing lists with their items.
+func readManifests(stdin io.Reader, paths []string) ([]*unstructured.Unstructured, error) {
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	var objects []*unstructured.Unstructured
+	for _, path := range paths {
+		data, err := readManifestSource(stdin, path)
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := decodeManifests(data)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", describeSource(path), err)
+		}
+		objects = append(objects, decoded...)
+	}
+	return objects, nil
+}
+
+func readManifestSource(stdin io.Reader, path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifests: %w", err)
+	}
+	return data, nil
+}
+
+func describeSource(path string) string {
+	if path == "-" {
+		return "stdin"
+	}
+	return path
+}
+
+// decodeManifests decodes a stream of YAML or JSON documents, skipping empty ones.
+func decodeManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	var objects []*unstructured.Unstructured
+	for document := 1; ; document++ {
+		var content map[string]interface{}
+		if err := decoder.Decode(&content); errors.Is(err, io.EOF) {
+			return objects, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("document %d: %w", document, err)
+		}
+		if len(content) == 0 {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: content}
+		if !obj.IsList() {
+			objects = append(objects, obj)
+			continue
+		}
+		list, err := obj.ToList()
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", document, err)
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	}
+}
+
+// matchingRule returns the first rule that would collect the object, preferring explicit rules over wildcard rules as
+// API discovery does. Namespace and field selectors need the cluster, so they are not consulted. An object that no rule
+// matches gets a rule of its own, which uses the global filters.
+func matchingRule(cfg *config.Config, obj *unstructured.Unstructured) config.ObjectRule {
+	for _, wildcard := range []bool{false, true} {
+		for _, rule := range cfg.Objects {
+			if rule.IsWildcard() != wildcard || !ruleMatches(cfg, rule, obj) {
+				continue
+			}
+			if wildcard {
+				rule.APIVersion = obj.GetAPIVersion()
+				rule.Kind = obj.GetKind()
+				rule.ExcludeKinds = nil
+			}
+			return rule
+		}
+	}
+	return config.ObjectRule{APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind()}
+}
+
+func ruleMatches(cfg *config.Config, rule config.ObjectRule, obj *unstructured.Unstructured) bool {
+	if rule.IsWildcard() {
+		if !wildcardMatches(rule, obj) {
+			return false
+		}
+	} else if rule.APIVersion != obj.GetAPIVersion() || rule.Kind != obj.GetKind() {
+		return false
+	}
+	if !rule.MatchesName(obj.GetName()) {
+		return false
+	}
+	if selector := strings.TrimSpace(rule.LabelSelector); selector != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil || !parsed.Matches(labels.Set(obj.GetLabels())) {
+			return false
+		}
+	}
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		return true
+	}
+	if discovery.ShouldExcludeNamespace(namespace, cfg.ExcludeNamespaces) {
+		return false
+	}
+	included := discovery.EffectiveNamespaces(rule, cfg)
+	return len(included) == 0 || config.NamespaceListMatches(included, namespace)
+}
+
+// wildcardMatches reports whether a wildcard rule covers the object's API group, or group version, and kind.
+func wildcardMatches(rule config.ObjectRule, obj *unstructured.Unstructured) bool {
+	if slices.Contains(rule.ExcludeKinds, obj.GetKind()) {
+		return false
+	}
+	if group, ok := strings.CutSuffix(rule.APIVersion, "/"+config.WildcardVersion); ok {
+		gv, err := schema.ParseGroupVersion(obj.GetAPIVersion())
+		return err == nil && gv.Group == group
+	}
+	return rule.APIVersion == obj.GetAPIVersion()
+}
+
+func describeObject(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// previewProcessor ends the filter chain of the filter command: it returns each filtered object instead of writing it.
+type previewProcessor struct{}
+
+func (previewProcessor) Process(_ config.ObjectRule, obj *unstructured.Unstructured, _ *config.Config) (*manifest.Diff, error) {
+	return &manifest.Diff{Current: obj.DeepCopy()}, nil
+}
+
+func (previewProcessor) Delete(config.ObjectRule, *unstructured.Unstructured, *config.Config) error {
+	return nil
+}
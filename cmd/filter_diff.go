@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// unifiedDiff compares two texts line by line and renders their differences as a unified diff, or returns "" when
+// they are equal.
+func unifiedDiff(fromLabel, toLabel string, from, to []byte) string {
+	// Writing to the string builder behind GetUnifiedDiffString cannot fail.
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(from),
+		B:        splitLines(to),
+		FromFile: fromLabel,
+		ToFile:   toLabel,
+		Context:  diffContext,
+	})
+	return diff
+}
+
+// splitLines splits a text into lines that each end with a newline, as difflib expects.
+func splitLines(text []byte) []string {
+	lines := strings.SplitAfter(string(text), "\n")
+	last := len(lines) - 1
+	if lines[last] == "" {
+		return lines[:last]
+	}
+	lines[last] += "\n"
+	return lines
+}
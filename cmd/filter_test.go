@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+const filterTestConfig = `
+filters:
+  - name: removeStatus
+objects:
+  - apiVersion: v1
+    kind: ConfigMap
+    namespaces: [prod]
+    filters:
+      - name: redactData
+        keyPatterns: [password]
+      - name: jq
+        program: 'select(.metadata.name != "drop")'
+  - apiVersion: apps/*
+    kind: "*"
+    filters:
+      - name: removeFields
+        paths: [spec.replicas]
+`
+
+func writeTempManifests(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "manifests.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("write temp manifests: %v", err)
+	}
+	return path
+}
+
+func TestFilterCommandAppliesTheMatchingRule(t *testing.T) {
+	state := snapshotFlags()
+	defer restoreFlags(state)
+	defer ResetConfiguration()
+
+	g := gomega.NewWithT(t)
+
+	manifests := writeTempManifests(t, `
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata: {name: api, namespace: prod}
+    data: {password: hunter2, mode: fast}
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata: {name: drop, namespace: prod}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: api, namespace: dev}
+data: {password: hunter2}
+status: {phase: Ready}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: web, namespace: prod}
+spec: {replicas: 3, paused: true}
+`)
+
+	var stdout, stderr bytes.Buffer
+	err := ExecuteWithArgs([]string{"filter", "--config", writeTempConfigFile(t, filterTestConfig), manifests}, &stdout, &stderr)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(stdout.String()).To(gomega.Equal(`apiVersion: v1
+data:
+  mode: fast
+  password: <Redacted>
+kind: ConfigMap
+metadata:
+  name: api
+  namespace: prod
+---
+apiVersion: v1
+data:
+  password: hunter2
+kind: ConfigMap
+metadata:
+  name: api
+  namespace: dev
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  paused: true
+`))
+	g.Expect(stderr.String()).To(gomega.Equal("Dropped ConfigMap prod/drop\n"))
+}
+
+func TestFilterCommandPrintsDiffs(t *testing.T) {
+	state := snapshotFlags()
+	defer restoreFlags(state)
+	defer ResetConfiguration()
+	defer func() { filterDiff = false }()
+
+	g := gomega.NewWithT(t)
+
+	rootCmd.SetIn(strings.NewReader(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "api", "namespace": "prod"}, "data": {"password": "hunter2"}}
+{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "plain", "namespace": "prod"}}
+{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "drop", "namespace": "prod"}}
+`))
+	defer rootCmd.SetIn(nil)
+
+	var stdout, stderr bytes.Buffer
+	err := ExecuteWithArgs([]string{"filter", "--diff", "--config", writeTempConfigFile(t, filterTestConfig), "-"}, &stdout, &stderr)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(stdout.String()).To(gomega.Equal(`--- ConfigMap prod/api (input)
++++ ConfigMap prod/api (filtered)
+@@ -1,6 +1,6 @@
+ apiVersion: v1
+ data:
+-  password: hunter2
++  password: <Redacted>
+ kind: ConfigMap
+ metadata:
+   name: api
+--- ConfigMap prod/drop (input)
++++ ConfigMap prod/drop (dropped)
+@@ -1,5 +0,0 @@
+-apiVersion: v1
+-kind: ConfigMap
+-metadata:
+-  name: drop
+-  namespace: prod
+`))
+	g.Expect(stderr.String()).To(gomega.BeEmpty())
+}
+
+func TestFilterCommandReportsUnreadableManifests(t *testing.T) {
+	state := snapshotFlags()
+	defer restoreFlags(state)
+	defer ResetConfiguration()
+
+	g := gomega.NewWithT(t)
+
+	manifests := writeTempManifests(t, "apiVersion: v1\nkind: ConfigMap\n---\n[unclosed\n")
+
+	var stdout, stderr bytes.Buffer
+	err := ExecuteWithArgs([]string{"filter", "--config", writeTempConfigFile(t, filterTestConfig), manifests}, &stdout, &stderr)
+	g.Expect(err).To(gomega.MatchError(gomega.HavePrefix("decode " + manifests + ": document 2: ")))
+	g.Expect(stdout.String()).To(gomega.BeEmpty())
+}
+
+func TestUnifiedDiffSplitsDistantChanges(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	from := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	to := "A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\nk\n"
+
+	g.Expect(unifiedDiff("from", "to", []byte(from), []byte(to))).To(gomega.Equal(`--- from
++++ to
+@@ -1,4 +1,4 @@
+-a
++A
+ b
+ c
+ d
+@@ -7,4 +7,5 @@
+ g
+ h
+ i
+-j
++J
++k
+`))
+	g.Expect(unifiedDiff("from", "to", []byte(from), []byte(from))).To(gomega.BeEmpty())
+}
+
+func TestUnifiedDiffHandlesLargeManifests(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	var from, to strings.Builder
+	for i := range 20000 {
+		line := fmt.Sprintf("line %d\n", i)
+		from.WriteString(line)
+		if i == 10000 {
+			to.WriteString("changed\n")
+			continue
+		}
+		to.WriteString(line)
+	}
+
+	g.Expect(unifiedDiff("from", "to", []byte(from.String()), []byte(to.String()))).To(gomega.Equal(`--- from
++++ to
+@@ -9998,7 +9998,7 @@
+ line 9997
+ line 9998
+ line 9999
+-line 10000
++changed
+ line 10001
+ line 10002
+ line 10003
+`))
+}
+
+func TestFilterCommandUsesTheRuleOutputFormat(t *testing.T) {
+	state := snapshotFlags()
+	defer restoreFlags(state)
+	defer ResetConfiguration()
+
+	g := gomega.NewWithT(t)
+
+	path := writeTempConfigFile(t, `
+objects:
+  - apiVersion: v1
+    kind: Secret
+    output:
+      format: json
+  - apiVersion: v1
+    kind: ConfigMap
+`)
+	manifests := writeTempManifests(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: first}
+---
+apiVersion: v1
+kind: Secret
+metadata: {name: token}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: second}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: third}
+`)
+
+	var stdout, stderr bytes.Buffer
+	g.Expect(ExecuteWithArgs([]string{"filter", "--config", path, manifests}, &stdout, &stderr)).To(gomega.Succeed())
+	g.Expect(stdout.String()).To(gomega.Equal(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+{
+  "apiVersion": "v1",
+  "kind": "Secret",
+  "metadata": {
+    "name": "token"
+  }
+}
+
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: third
+`))
+}
@@ -31,10 +31,14 @@ func runList(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("create kubernetes clients: %w", err)
 	}
+	cfg, err := expandConfiguration(clients, Configuration)
+	if err != nil {
+		return err
+	}
 
-	fetcher := discovery.NewFetcher(clients, Configuration)
+	fetcher := discovery.NewFetcher(clients, cfg)
 	printer := &listPrinter{out: cmd.OutOrStdout()}
-	for _, rule := range Configuration.Objects {
+	for _, rule := range cfg.Objects {
 		items, err := fetcher.FetchResources(ctx, rule)
 		if err != nil {
 			return err
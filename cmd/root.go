@@ -2,17 +2,21 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"io"
 )
 
+const defaultConfigPath = "config.yaml"
+
 var (
-	configPath                string
+	configPaths               []string
 	kubeconfigOverride        string
 	outputDirOverride         string
 	outputFormatOverride      string
 	refreshIntervalOverride   string
 	namespacesOverride        []string
 	excludeNamespacesOverride []string
+	namespaceSelectorOverride string
 
 	rootCmd = &cobra.Command{
 		Use:   "k8s-manifest-tail",
@@ -20,6 +24,14 @@ var (
 	}
 )
 
+// configFiles returns the configuration paths to load, falling back to config.yaml in the working directory.
+func configFiles() []string {
+	if len(configPaths) == 0 {
+		return []string{defaultConfigPath}
+	}
+	return configPaths
+}
+
 // Execute runs the CLI with the default arguments.
 func Execute() error {
 	return rootCmd.Execute()
@@ -27,6 +39,11 @@ func Execute() error {
 
 // ExecuteWithArgs executes the CLI with the provided arguments and IO writers.
 func ExecuteWithArgs(args []string, stdout, stderr io.Writer) error {
+	// Repeated --config flags append to the paths of any previous execution, so start from an empty list.
+	if flag := rootCmd.PersistentFlags().Lookup("config"); flag != nil {
+		_ = flag.Value.(pflag.SliceValue).Replace(nil)
+		flag.Changed = false
+	}
 	rootCmd.SetArgs(args)
 	rootCmd.SetOut(stdout)
 	rootCmd.SetErr(stderr)
@@ -34,11 +51,12 @@ func ExecuteWithArgs(args []string, stdout, stderr io.Writer) error {
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "config.yaml", "Path to configuration file")
+	rootCmd.PersistentFlags().StringArrayVarP(&configPaths, "config", "c", nil, "Path to a configuration file or directory, repeatable and merged in order (default config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&kubeconfigOverride, "kubeconfig", "", "Path to kubeconfig (overrides config file)")
 	rootCmd.PersistentFlags().StringVarP(&outputDirOverride, "output-directory", "o", "", "Directory for manifest output (overrides config file)")
 	rootCmd.PersistentFlags().StringVarP(&outputFormatOverride, "output-format", "f", "", "Output format: yaml or json (overrides config file)")
 	rootCmd.PersistentFlags().StringVar(&refreshIntervalOverride, "refresh-interval", "", "Interval for full refresh (overrides config file)")
 	rootCmd.PersistentFlags().StringSliceVarP(&namespacesOverride, "namespaces", "n", nil, "Namespaces to include globally (overrides config file)")
 	rootCmd.PersistentFlags().StringSliceVar(&excludeNamespacesOverride, "exclude-namespaces", nil, "Namespaces to exclude globally (overrides config file)")
+	rootCmd.PersistentFlags().StringVar(&namespaceSelectorOverride, "namespace-selector", "", "Label selector for namespaces to include globally (overrides config file)")
 }
@@ -14,7 +14,7 @@ func TestLoadConfigWithOverrides(t *testing.T) {
 
 	g := gomega.NewWithT(t)
 
-	configPath = writeTempConfigFile(t, `
+	configPaths = []string{writeTempConfigFile(t, `
 output:
   directory: from-config
   format: yaml
@@ -23,7 +23,7 @@ namespaces: ["default"]
 objects:
   - apiVersion: v1
     kind: Pod
-`)
+`)}
 
 	kubeconfigOverride = "/tmp/flag-kubeconfig"
 	outputDirOverride = "custom-dir"
@@ -31,6 +31,7 @@ objects:
 	refreshIntervalOverride = "2h"
 	namespacesOverride = []string{"prod"}
 	excludeNamespacesOverride = []string{"kube-system"}
+	namespaceSelectorOverride = "tenant=true"
 
 	err := LoadConfiguration(nil, nil)
 
@@ -41,6 +42,7 @@ objects:
 	g.Expect(Configuration.RefreshInterval).To(gomega.Equal("2h"))
 	g.Expect(Configuration.Namespaces).To(gomega.Equal([]string{"prod"}))
 	g.Expect(Configuration.ExcludeNamespaces).To(gomega.Equal([]string{"kube-system"}))
+	g.Expect(Configuration.NamespaceSelector).To(gomega.Equal("tenant=true"))
 }
 
 func TestLoadConfigWithOverridesInvalidOutputFormat(t *testing.T) {
@@ -49,14 +51,14 @@ func TestLoadConfigWithOverridesInvalidOutputFormat(t *testing.T) {
 
 	g := gomega.NewWithT(t)
 
-	configPath = writeTempConfigFile(t, `
+	configPaths = []string{writeTempConfigFile(t, `
 output:
   directory: out
   format: foo
 objects:
   - apiVersion: v1
     kind: Service
-`)
+`)}
 
 	err := LoadConfiguration(nil, nil)
 
@@ -65,35 +67,38 @@ objects:
 }
 
 type flagState struct {
-	configPath                string
+	configPaths               []string
 	kubeconfigOverride        string
 	outputDirOverride         string
 	outputFormatOverride      string
 	refreshIntervalOverride   string
 	namespacesOverride        []string
 	excludeNamespacesOverride []string
+	namespaceSelectorOverride string
 }
 
 func snapshotFlags() flagState {
 	return flagState{
-		configPath:                configPath,
+		configPaths:               cloneSlice(configPaths),
 		kubeconfigOverride:        kubeconfigOverride,
 		outputDirOverride:         outputDirOverride,
 		outputFormatOverride:      outputFormatOverride,
 		refreshIntervalOverride:   refreshIntervalOverride,
 		namespacesOverride:        cloneSlice(namespacesOverride),
 		excludeNamespacesOverride: cloneSlice(excludeNamespacesOverride),
+		namespaceSelectorOverride: namespaceSelectorOverride,
 	}
 }
 
 func restoreFlags(state flagState) {
-	configPath = state.configPath
+	configPaths = cloneSlice(state.configPaths)
 	kubeconfigOverride = state.kubeconfigOverride
 	outputDirOverride = state.outputDirOverride
 	outputFormatOverride = state.outputFormatOverride
 	refreshIntervalOverride = state.refreshIntervalOverride
 	namespacesOverride = cloneSlice(state.namespacesOverride)
 	excludeNamespacesOverride = cloneSlice(state.excludeNamespacesOverride)
+	namespaceSelectorOverride = state.namespaceSelectorOverride
 }
 
 func cloneSlice(values []string) []string {
@@ -4,13 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+	"github.com/grafana/k8s-manifest-tail/internal/kube"
 	"github.com/grafana/k8s-manifest-tail/internal/logging"
 	"github.com/grafana/k8s-manifest-tail/internal/telemetry"
 	"github.com/grafana/k8s-manifest-tail/pkg"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"reflect"
+	"sync"
 	"time"
 )
 
+var configReloadInterval time.Duration
+
 var runAndWatchCmd = &cobra.Command{
 	Use:     "run",
 	Short:   "Fetch manifests and keep watching for changes",
@@ -19,6 +27,7 @@ var runAndWatchCmd = &cobra.Command{
 }
 
 func init() {
+	runAndWatchCmd.Flags().DurationVar(&configReloadInterval, "config-reload-interval", 10*time.Second, "How often to check the config file for changes (0 disables reloading)")
 	rootCmd.AddCommand(runAndWatchCmd)
 }
 
@@ -30,6 +39,10 @@ func runRun(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("create kubernetes clients: %w", err)
 	}
+	cfg, err := expandConfiguration(clients, Configuration)
+	if err != nil {
+		return err
+	}
 
 	logger, shutdownTelemetry, err := telemetry.SetupLogging(ctx, Configuration.Logging, cmd.OutOrStdout())
 	if err != nil {
@@ -46,37 +59,44 @@ func runRun(cmd *cobra.Command, args []string) error {
 
 	tail := pkg.Tail{
 		Clients:        clients,
-		Config:         Configuration,
+		Config:         cfg,
 		DiffLogger:     diffLogger,
 		ManifestLogger: manifestLogger,
 		Metrics:        metrics,
-		Processor:      GetManifestProcessor(Configuration),
+		Processor:      GetManifestProcessor(cfg, clients, logger),
+		Logger:         logger,
 	}
+	// Deferred calls run last in, first out, so the processor is closed only after the goroutines below have returned.
+	defer func() { CloseManifestProcessor(tail.CurrentProcessor()) }()
+	var background sync.WaitGroup
+	defer background.Wait()
 
-	refreshErrCh := make(chan error, 1)
-	refreshInterval, _ := Configuration.GetRefreshInterval() // Error checked during config validation
-	ticker := time.NewTicker(refreshInterval)
-	defer ticker.Stop()
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				total, runErr := tail.RunFullManifestCheck(ctx)
-				if runErr != nil {
-					if !errors.Is(runErr, context.Canceled) {
-						select {
-						case refreshErrCh <- runErr:
-						default:
-						}
-					}
-					cancel()
+	if configReloadInterval > 0 {
+		watcher, err := config.NewWatcher(configReloadInterval, configFiles()...)
+		if err != nil {
+			return fmt.Errorf("watch configuration: %w", err)
+		}
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			watcher.Run(ctx, func() {
+				if reloadErr := reloadConfiguration(clients, &tail); reloadErr != nil {
+					telemetry.Warn(logger, fmt.Sprintf("Ignoring invalid configuration change, keeping the previous configuration: %v", reloadErr))
 					return
 				}
-				telemetry.Info(logger, fmt.Sprintf("Fetched %d manifest(s)", total))
-			}
+				telemetry.Info(logger, "Reloaded configuration")
+			})
+		}()
+	}
+	refreshErrCh := make(chan error, 1)
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		runErr := tail.RunScheduledRefreshes(ctx)
+		if runErr != nil && !errors.Is(runErr, context.Canceled) {
+			cancel()
 		}
+		refreshErrCh <- runErr
 	}()
 
 	err = tail.WatchResources(ctx)
@@ -84,12 +104,53 @@ func runRun(cmd *cobra.Command, args []string) error {
 	if err != nil && !errors.Is(err, context.Canceled) {
 		return err
 	}
-	select {
-	case runErr := <-refreshErrCh:
-		if runErr != nil && !errors.Is(runErr, context.Canceled) {
-			return runErr
-		}
-	default:
+	if runErr := <-refreshErrCh; runErr != nil && !errors.Is(runErr, context.Canceled) {
+		return runErr
 	}
 	return nil
 }
+
+// reloadConfiguration re-reads the configuration and applies it to the running tail. Only the watches and refresh
+// schedules of rules that were added, removed, or changed are restarted, and the processor is rebuilt when the output
+// or filter settings change. The tail stops the plugins of the previous processor once the objects it is filtering are
+// done. An invalid configuration is returned as an error and leaves the previous configuration in place.
+func reloadConfiguration(clients *kube.Clients, tail *pkg.Tail) error {
+	cfg, err := buildConfiguration()
+	if err != nil {
+		return err
+	}
+	invalidateDiscovery(clients)
+	expanded, err := expandConfiguration(clients, cfg)
+	if err != nil {
+		return err
+	}
+
+	previous := Configuration
+	processor := GetManifestProcessor(expanded, clients, tail.Logger)
+	if !reflect.DeepEqual(previous.Output, cfg.Output) || !reflect.DeepEqual(filterSettings(previous), filterSettings(cfg)) {
+		processor = RebuildManifestProcessor(expanded, clients, tail.Logger)
+	}
+	Configuration = cfg
+	tail.Reload(expanded, processor)
+	return nil
+}
+
+// invalidateDiscovery drops the cached API discovery, so that wildcard rules and kinds resolved after a reload include
+// resources installed since the cache was filled.
+func invalidateDiscovery(clients *kube.Clients) {
+	if mapper, ok := clients.Mapper.(meta.ResettableRESTMapper); ok {
+		mapper.Reset()
+	}
+	if cached, ok := clients.Discovery.(discovery.CachedDiscoveryInterface); ok {
+		cached.Invalidate()
+	}
+}
+
+// filterSettings collects the global filters and those of every rule, so that a reload can tell whether they changed.
+func filterSettings(cfg *config.Config) [][]config.FilterConfig {
+	settings := [][]config.FilterConfig{cfg.Filters}
+	for _, rule := range cfg.Objects {
+		settings = append(settings, rule.Filters)
+	}
+	return settings
+}
@@ -1,26 +1,57 @@
 package cmd
 
 import (
+	"io"
+
+	"go.opentelemetry.io/otel/log"
+
 	"github.com/grafana/k8s-manifest-tail/internal/config"
+	"github.com/grafana/k8s-manifest-tail/internal/kube"
 	"github.com/grafana/k8s-manifest-tail/internal/manifest"
 )
 
-var manifestProcessor manifest.Processor
+var (
+	manifestProcessor       manifest.Processor
+	manifestProcessorCustom bool
+)
 
-func GetManifestProcessor(cfg *config.Config) manifest.Processor {
+func GetManifestProcessor(cfg *config.Config, clients *kube.Clients, logger log.Logger) manifest.Processor {
 	if manifestProcessor == nil {
-		manifestProcessor = manifest.NewFilterProcessor(
-			manifest.NewWriter(cfg.Output),
-			manifest.RemoveStatusFilter{},
-			manifest.RemoveMetadataFieldsFilter{},
-			manifest.RedactEnvValuesFilter{},
-		)
+		manifestProcessor = NewManifestProcessor(cfg, clients, logger)
 	}
 
 	return manifestProcessor
 }
 
+// NewManifestProcessor builds the processor that applies each rule's filter chain and writes the result according to
+// the supplied configuration. Filters that consult the cluster use clients, which may be nil, and log their warnings to
+// logger, which may also be nil.
+func NewManifestProcessor(cfg *config.Config, clients *kube.Clients, logger log.Logger) manifest.Processor {
+	env := manifest.FilterEnvironment{Logger: logger}
+	if clients != nil {
+		env.OpenAPI = clients.OpenAPI
+	}
+	return manifest.NewRuleFilters(manifest.NewRuleWriters(cfg.Output), env)
+}
+
+// RebuildManifestProcessor replaces the default processor with one built for cfg. A processor installed with
+// SetManifestProcessor is kept as is.
+func RebuildManifestProcessor(cfg *config.Config, clients *kube.Clients, logger log.Logger) manifest.Processor {
+	if !manifestProcessorCustom {
+		manifestProcessor = NewManifestProcessor(cfg, clients, logger)
+	}
+	return manifestProcessor
+}
+
+// CloseManifestProcessor stops the plugins started by a processor, if it started any.
+func CloseManifestProcessor(processor manifest.Processor) {
+	if closer, ok := processor.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
 // SetManifestProcessor overrides the manifest processor used by the run command (primarily for tests).
 func SetManifestProcessor(p manifest.Processor) {
 	manifestProcessor = p
+	manifestProcessorCustom = p != nil
 }
@@ -31,6 +31,10 @@ func runRunOnce(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("create kubernetes clients: %w", err)
 	}
+	cfg, err := expandConfiguration(clients, Configuration)
+	if err != nil {
+		return err
+	}
 
 	logger, shutdownTelemetry, err := telemetry.SetupLogging(ctx, Configuration.Logging, cmd.OutOrStdout())
 	if err != nil {
@@ -47,12 +51,14 @@ func runRunOnce(cmd *cobra.Command, args []string) error {
 
 	tail := pkg.Tail{
 		Clients:        clients,
-		Config:         Configuration,
+		Config:         cfg,
 		DiffLogger:     diffLogger,
 		ManifestLogger: manifestLogger,
-		Processor:      GetManifestProcessor(Configuration),
+		Processor:      GetManifestProcessor(cfg, clients, logger),
 		Metrics:        metrics,
+		Logger:         logger,
 	}
+	defer CloseManifestProcessor(tail.CurrentProcessor())
 
 	total, err := tail.RunFullManifestCheck(ctx)
 	if err != nil {
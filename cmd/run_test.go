@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	memdiscovery "k8s.io/client-go/discovery/cached/memory"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/grafana/k8s-manifest-tail/internal/kube"
+	"github.com/grafana/k8s-manifest-tail/pkg"
+)
+
+func TestReloadConfigurationAppliesChanges(t *testing.T) {
+	state := snapshotFlags()
+	defer restoreFlags(state)
+	defer ResetConfiguration()
+	defer SetManifestProcessor(nil)
+
+	g := gomega.NewWithT(t)
+
+	configPaths = []string{writeTempConfigFile(t, `
+output:
+  directory: first
+objects:
+  - apiVersion: v1
+    kind: Pod
+`)}
+	g.Expect(LoadConfiguration(nil, nil)).To(gomega.Succeed())
+	SetManifestProcessor(nil)
+	original := GetManifestProcessor(Configuration, nil, nil)
+	tail := &pkg.Tail{Clients: &kube.Clients{}, Config: Configuration, Processor: original}
+
+	g.Expect(os.WriteFile(configPaths[0], []byte(`
+output:
+  directory: second
+objects:
+  - apiVersion: v1
+    kind: Service
+`), 0o600)).To(gomega.Succeed())
+
+	g.Expect(reloadConfiguration(tail.Clients, tail)).To(gomega.Succeed())
+	g.Expect(Configuration.Output.Directory).To(gomega.Equal("second"))
+	g.Expect(tail.Config.Objects[0].Kind).To(gomega.Equal("Service"))
+	g.Expect(tail.Processor).NotTo(gomega.BeIdenticalTo(original))
+}
+
+func TestReloadConfigurationKeepsPreviousOnError(t *testing.T) {
+	state := snapshotFlags()
+	defer restoreFlags(state)
+	defer ResetConfiguration()
+	defer SetManifestProcessor(nil)
+
+	g := gomega.NewWithT(t)
+
+	configPaths = []string{writeTempConfigFile(t, `
+objects:
+  - apiVersion: v1
+    kind: Pod
+`)}
+	g.Expect(LoadConfiguration(nil, nil)).To(gomega.Succeed())
+	previous := Configuration
+	tail := &pkg.Tail{Clients: &kube.Clients{}, Config: Configuration, Processor: GetManifestProcessor(Configuration, nil, nil)}
+
+	g.Expect(os.WriteFile(configPaths[0], []byte(`
+objects:
+  - apiVersion: v1
+    kind: Pod
+    labelSelector: "app in ("
+`), 0o600)).To(gomega.Succeed())
+
+	err := reloadConfiguration(tail.Clients, tail)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(Configuration).To(gomega.BeIdenticalTo(previous))
+	g.Expect(tail.Config).To(gomega.BeIdenticalTo(previous))
+}
+
+func TestReloadConfigurationRebuildsProcessorWhenFiltersChange(t *testing.T) {
+	state := snapshotFlags()
+	defer restoreFlags(state)
+	defer ResetConfiguration()
+	defer SetManifestProcessor(nil)
+
+	g := gomega.NewWithT(t)
+
+	configPaths = []string{writeTempConfigFile(t, `
+objects:
+  - apiVersion: v1
+    kind: Pod
+`)}
+	g.Expect(LoadConfiguration(nil, nil)).To(gomega.Succeed())
+	SetManifestProcessor(nil)
+	original := GetManifestProcessor(Configuration, nil, nil)
+	tail := &pkg.Tail{Clients: &kube.Clients{}, Config: Configuration, Processor: original}
+
+	g.Expect(reloadConfiguration(tail.Clients, tail)).To(gomega.Succeed())
+	g.Expect(tail.Processor).To(gomega.BeIdenticalTo(original))
+
+	g.Expect(os.WriteFile(configPaths[0], []byte(`
+objects:
+  - apiVersion: v1
+    kind: Pod
+    filters:
+      - name: plugin
+        command: [/usr/local/bin/redact]
+`), 0o600)).To(gomega.Succeed())
+
+	g.Expect(reloadConfiguration(tail.Clients, tail)).To(gomega.Succeed())
+	g.Expect(tail.Processor).NotTo(gomega.BeIdenticalTo(original))
+}
+
+func TestReloadConfigurationDiscoversNewKinds(t *testing.T) {
+	state := snapshotFlags()
+	defer restoreFlags(state)
+	defer ResetConfiguration()
+	defer SetManifestProcessor(nil)
+
+	g := gomega.NewWithT(t)
+
+	resources := &metav1.APIResourceList{GroupVersion: "v1", APIResources: []metav1.APIResource{
+		{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: metav1.Verbs{"list", "watch"}},
+	}}
+	fake := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{resources}}}
+	clients := &kube.Clients{Discovery: memdiscovery.NewMemCacheClient(fake)}
+
+	configPaths = []string{writeTempConfigFile(t, `
+objects:
+  - apiVersion: v1
+    kind: "*"
+`)}
+	g.Expect(LoadConfiguration(nil, nil)).To(gomega.Succeed())
+	expanded, err := expandConfiguration(clients, Configuration)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(expanded.Objects).To(gomega.HaveLen(1))
+	tail := &pkg.Tail{Clients: clients, Config: expanded, Processor: GetManifestProcessor(expanded, nil, nil)}
+
+	fake.Resources = []*metav1.APIResourceList{{GroupVersion: "v1", APIResources: append([]metav1.APIResource{
+		{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: metav1.Verbs{"list", "watch"}},
+	}, resources.APIResources...)}}
+	g.Expect(reloadConfiguration(clients, tail)).To(gomega.Succeed())
+	kinds := make([]string, 0, len(tail.Config.Objects))
+	for _, rule := range tail.Config.Objects {
+		kinds = append(kinds, rule.Kind)
+	}
+	g.Expect(kinds).To(gomega.Equal([]string{"ConfigMap", "Pod"}))
+}
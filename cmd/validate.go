@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+var validateCmd = &cobra.Command{
+	Use:          "validate",
+	Short:        "Report every problem in the configuration files",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runValidate,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+// runValidate loads and validates the configuration files as written, without flag overrides or cluster access, and
+// prints each problem with the file, line, and column it came from.
+func runValidate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(configFiles()...)
+	if err != nil {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), err)
+		return fmt.Errorf("configuration could not be loaded")
+	}
+
+	problems := cfg.Problems()
+	if len(cfg.Objects) == 0 {
+		problems = append(problems, config.Problem{Path: "objects", Err: errors.New("no objects found in configuration file")})
+	}
+	for _, problem := range problems {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), problem.Error())
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s) in the configuration", len(problems))
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Configuration is valid")
+	return nil
+}
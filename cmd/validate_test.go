@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestValidateCommandReportsEveryProblem(t *testing.T) {
+	state := snapshotFlags()
+	defer restoreFlags(state)
+
+	g := gomega.NewWithT(t)
+
+	path := writeTempConfigFile(t, `
+refreshInterval: soon
+objects:
+  - apiVersion: v1
+    kind: Pod
+    labelSelector: "tier in ("
+`)
+
+	var stdout, stderr bytes.Buffer
+	err := ExecuteWithArgs([]string{"validate", "--config", path}, &stdout, &stderr)
+	g.Expect(err).To(gomega.MatchError("found 2 problem(s) in the configuration"))
+	g.Expect(stdout.String()).To(gomega.ContainSubstring(path + `:6:5: objects[0].labelSelector: invalid labelSelector "tier in ("`))
+	g.Expect(stdout.String()).To(gomega.ContainSubstring(path + `:2:1: refreshInterval: invalid refresh interval "soon"`))
+}
+
+func TestValidateCommandAcceptsValidConfiguration(t *testing.T) {
+	state := snapshotFlags()
+	defer restoreFlags(state)
+
+	g := gomega.NewWithT(t)
+
+	path := writeTempConfigFile(t, `
+objects:
+  - apiVersion: v1
+    kind: Pod
+`)
+
+	var stdout, stderr bytes.Buffer
+	g.Expect(ExecuteWithArgs([]string{"validate", "--config", path}, &stdout, &stderr)).To(gomega.Succeed())
+	g.Expect(stdout.String()).To(gomega.Equal("Configuration is valid\n"))
+}
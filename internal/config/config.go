@@ -1,14 +1,19 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/grafana/k8s-manifest-tail/internal/schedule"
 )
 
 // OutputFormat enumerates the supported serialization formats.
@@ -16,55 +21,138 @@ type OutputFormat string
 
 const (
 	DefaultRefreshInterval string       = "24h"
+	WildcardKind           string       = "*"
+	WildcardVersion        string       = "*"
 	OutputFormatYAML       OutputFormat = "yaml"
 	OutputFormatJSON       OutputFormat = "json"
 )
 
+// Validate ensures the format is one of the supported serialization formats.
+func (f OutputFormat) Validate() error {
+	switch f {
+	case OutputFormatYAML, OutputFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q (expected yaml or json)", f)
+	}
+}
+
 // OutputConfig controls how manifests are written.
 type OutputConfig struct {
-	Directory string       `mapstructure:"directory" yaml:"directory"`
-	Format    OutputFormat `mapstructure:"format" yaml:"format"`
+	Directory    string       `mapstructure:"directory" yaml:"directory"`
+	Format       OutputFormat `mapstructure:"format" yaml:"format"`
+	PathTemplate string       `mapstructure:"pathTemplate" yaml:"pathTemplate"`
+}
+
+// Merge returns the output settings with the fields set in override taking precedence.
+func (o OutputConfig) Merge(override *OutputConfig) OutputConfig {
+	if override == nil {
+		return o
+	}
+	if override.Directory != "" {
+		o.Directory = override.Directory
+	}
+	if override.Format != "" {
+		o.Format = override.Format
+	}
+	if override.PathTemplate != "" {
+		o.PathTemplate = override.PathTemplate
+	}
+	return o
+}
+
+func (o OutputConfig) problems() []Problem {
+	var problems []Problem
+	if o.Format != "" {
+		if err := o.Format.Validate(); err != nil {
+			problems = append(problems, Problem{Path: "format", Err: err})
+		}
+	}
+	if o.PathTemplate != "" {
+		if err := validatePathTemplate(o.PathTemplate); err != nil {
+			problems = append(problems, Problem{Path: "pathTemplate", Err: err})
+		}
+	}
+	return problems
 }
 
 // ObjectRule describes which Kubernetes objects to collect.
 type ObjectRule struct {
-	APIVersion  string   `mapstructure:"apiVersion" yaml:"apiVersion"`
-	Kind        string   `mapstructure:"kind" yaml:"kind"`
-	Namespaces  []string `mapstructure:"namespaces" yaml:"namespaces"`
-	NamePattern string   `mapstructure:"namePattern" yaml:"namePattern"`
+	APIVersion          string         `mapstructure:"apiVersion" yaml:"apiVersion"`
+	Kind                string         `mapstructure:"kind" yaml:"kind"`
+	Namespaces          []string       `mapstructure:"namespaces" yaml:"namespaces"`
+	NamespaceSelector   string         `mapstructure:"namespaceSelector" yaml:"namespaceSelector"`
+	NamePattern         string         `mapstructure:"namePattern" yaml:"namePattern"`
+	NamePatterns        []string       `mapstructure:"namePatterns" yaml:"namePatterns"`
+	ExcludeNamePatterns []string       `mapstructure:"excludeNamePatterns" yaml:"excludeNamePatterns"`
+	ExcludeKinds        []string       `mapstructure:"excludeKinds" yaml:"excludeKinds"`
+	LabelSelector       string         `mapstructure:"labelSelector" yaml:"labelSelector"`
+	FieldSelector       string         `mapstructure:"fieldSelector" yaml:"fieldSelector"`
+	RefreshInterval     string         `mapstructure:"refreshInterval" yaml:"refreshInterval"`
+	RefreshJitter       string         `mapstructure:"refreshJitter" yaml:"refreshJitter"`
+	Output              *OutputConfig  `mapstructure:"output" yaml:"output"`
+	Filters             []FilterConfig `mapstructure:"filters" yaml:"filters"`
+	Patches             []PatchConfig  `mapstructure:"patches" yaml:"patches"`
+
+	names  *nameMatcher
+	origin string
 }
 
 // Config captures all supported configuration settings.
 type Config struct {
-	Output                  OutputConfig  `mapstructure:"output" yaml:"output"`
-	Logging                 LoggingConfig `mapstructure:"logging" yaml:"logging"`
-	RefreshInterval         string        `mapstructure:"refreshInterval" yaml:"refreshInterval"`
-	RefreshIntervalDuration time.Duration
-	Namespaces              []string     `mapstructure:"namespaces" yaml:"namespaces"`
-	ExcludeNamespaces       []string     `mapstructure:"excludeNamespaces" yaml:"excludeNamespaces"`
-	Objects                 []ObjectRule `mapstructure:"objects" yaml:"objects"`
-	KubeconfigPath          string       `yaml:"-" mapstructure:"-"`
+	Output            OutputConfig   `mapstructure:"output" yaml:"output"`
+	Logging           LoggingConfig  `mapstructure:"logging" yaml:"logging"`
+	RefreshInterval   string         `mapstructure:"refreshInterval" yaml:"refreshInterval"`
+	RefreshJitter     string         `mapstructure:"refreshJitter" yaml:"refreshJitter"`
+	Namespaces        []string       `mapstructure:"namespaces" yaml:"namespaces"`
+	ExcludeNamespaces []string       `mapstructure:"excludeNamespaces" yaml:"excludeNamespaces"`
+	NamespaceSelector string         `mapstructure:"namespaceSelector" yaml:"namespaceSelector"`
+	Filters           []FilterConfig `mapstructure:"filters" yaml:"filters"`
+	Objects           []ObjectRule   `mapstructure:"objects" yaml:"objects"`
+	KubeconfigPath    string         `yaml:"-" mapstructure:"-"`
+
+	positions map[string]Position
 }
 
-// Load reads configuration data from the supplied file path.
-func Load(path string) (*Config, error) {
-	if path == "" {
+// Load reads configuration data from the supplied files and directories, merging them in order. Scalar settings from
+// later files win, while the object rules of every file are appended.
+func Load(paths ...string) (*Config, error) {
+	if len(paths) == 0 {
 		return nil, fmt.Errorf("config path is required")
 	}
-
-	file, err := os.Open(path)
+	files, err := ResolvePaths(paths)
 	if err != nil {
-		return nil, fmt.Errorf("open config: %w", err)
+		return nil, err
 	}
-	defer func(file *os.File) { _ = file.Close() }(file)
 
-	decoder := yaml.NewDecoder(file)
-	decoder.KnownFields(true)
+	// Every file is read before failing so that problems in several files are reported together.
+	sources := make([]*configSource, 0, len(files))
+	var errs []error
+	ruleOffset := 0
+	for _, file := range files {
+		source, err := readSource(file, ruleOffset)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sources = append(sources, source)
+		ruleOffset += len(source.origins)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	merged, origins, positions := mergeSources(sources)
 
 	var cfg Config
-	if err := decoder.Decode(&cfg); err != nil {
+	if err := merged.Decode(&cfg); err != nil {
 		return nil, fmt.Errorf("decode config: %w", err)
 	}
+	cfg.positions = positions
+	if len(origins) == len(cfg.Objects) {
+		for i := range cfg.Objects {
+			cfg.Objects[i].origin = origins[i]
+		}
+	}
 
 	ApplyEnvOverrides(&cfg)
 	return &cfg, nil
@@ -98,45 +186,121 @@ func ApplyEnvOverrides(cfg *Config) {
 	if value := strings.TrimSpace(os.Getenv("K8S_MANIFEST_TAIL_EXCLUDE_NAMESPACES")); value != "" {
 		cfg.ExcludeNamespaces = strings.Split(value, ",")
 	}
+	if value := strings.TrimSpace(os.Getenv("K8S_MANIFEST_TAIL_NAMESPACE_SELECTOR")); value != "" {
+		cfg.NamespaceSelector = value
+	}
 	if value := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_INSECURE")); value != "" {
 		insecure, _ := strconv.ParseBool(value)
 		cfg.Logging.OTLP.Insecure = insecure
 	}
 }
 
-// Validate ensures the configuration is internally consistent.
+// Validate ensures the configuration is internally consistent, returning the first problem found.
 func (cfg *Config) Validate() error {
-	if err := cfg.Logging.Validate(); err != nil {
-		return fmt.Errorf("validate logging config: %w", err)
+	if problems := cfg.Problems(); len(problems) > 0 {
+		return problems[0].validationError()
 	}
-	for i, rule := range cfg.Objects {
-		if err := rule.Validate(); err != nil {
-			return fmt.Errorf("validate object rule %d: %w", i+1, err)
+	return nil
+}
+
+// Problems validates the whole configuration and returns every problem found, located in the files it was loaded from.
+func (cfg *Config) Problems() []Problem {
+	var problems []Problem
+	add := func(path, context string, err error) {
+		problems = append(problems, Problem{Path: path, Err: err, context: context})
+	}
+
+	for _, problem := range cfg.Output.problems() {
+		problems = append(problems, problem.within("output", ""))
+	}
+	for _, problem := range cfg.Logging.problems() {
+		problems = append(problems, problem.within("logging", "validate logging config"))
+	}
+	for _, problem := range filterProblems(cfg.Filters) {
+		problems = append(problems, problem.within("filters", ""))
+	}
+	for i := range cfg.Objects {
+		for _, problem := range cfg.Objects[i].problems() {
+			problems = append(problems, problem.within(fmt.Sprintf("objects[%d]", i), "validate "+cfg.Objects[i].describeLocation(i)))
 		}
 	}
-	err := checkForDuplicates(cfg.Namespaces)
-	if err != nil {
-		return fmt.Errorf("global inclusion namespaces has duplicate: %w", err)
+	if index, err := checkForDuplicateRules(cfg.Objects); err != nil {
+		add(fmt.Sprintf("objects[%d]", index), "", err)
 	}
-	err = checkForDuplicates(cfg.ExcludeNamespaces)
-	if err != nil {
-		return fmt.Errorf("global exclusion namespaces has duplicate: %w", err)
+	if err := checkForDuplicates(cfg.Namespaces); err != nil {
+		add("namespaces", "global inclusion namespaces has duplicate", err)
 	}
-	_, err = cfg.GetRefreshInterval()
-	if err != nil {
-		return err
+	if err := checkForDuplicates(cfg.ExcludeNamespaces); err != nil {
+		add("excludeNamespaces", "global exclusion namespaces has duplicate", err)
 	}
-	return nil
+	if err := validateNamespacePatterns(cfg.Namespaces); err != nil {
+		add("namespaces", "global inclusion namespaces", err)
+	}
+	if err := validateNamespacePatterns(cfg.ExcludeNamespaces); err != nil {
+		add("excludeNamespaces", "global exclusion namespaces", err)
+	}
+	if strings.TrimSpace(cfg.NamespaceSelector) != "" {
+		if _, err := labels.Parse(cfg.NamespaceSelector); err != nil {
+			add("namespaceSelector", "", fmt.Errorf("invalid namespaceSelector %q: %w", cfg.NamespaceSelector, err))
+		}
+	}
+	if _, err := parseRefreshSchedule(cfg.RefreshInterval); err != nil {
+		add("refreshInterval", "", err)
+	}
+	if _, err := parseRefreshJitter(cfg.RefreshJitter); err != nil {
+		add("refreshJitter", "", err)
+	}
+
+	for i := range problems {
+		problems[i].Position = cfg.locate(problems[i].Path)
+	}
+	return problems
 }
 
-func (cfg *Config) GetRefreshInterval() (time.Duration, error) {
-	interval := cfg.RefreshInterval
+// GetOutput returns the output settings of a rule: the global output settings overridden by the rule's own.
+func (cfg *Config) GetOutput(rule ObjectRule) OutputConfig {
+	return cfg.Output.Merge(rule.Output)
+}
+
+// GetRefreshSchedule returns the full refresh schedule of a rule, falling back to the global refreshInterval. A nil
+// schedule means the rule is never refreshed after its initial listing.
+func (cfg *Config) GetRefreshSchedule(rule ObjectRule) (schedule.Schedule, error) {
+	if strings.TrimSpace(rule.RefreshInterval) != "" {
+		return parseRefreshSchedule(rule.RefreshInterval)
+	}
+	return parseRefreshSchedule(cfg.RefreshInterval)
+}
+
+// GetRefreshJitter returns the largest random delay added to each full refresh of a rule, falling back to the global
+// refreshJitter.
+func (cfg *Config) GetRefreshJitter(rule ObjectRule) (time.Duration, error) {
+	if strings.TrimSpace(rule.RefreshJitter) != "" {
+		return parseRefreshJitter(rule.RefreshJitter)
+	}
+	return parseRefreshJitter(cfg.RefreshJitter)
+}
+
+func parseRefreshSchedule(interval string) (schedule.Schedule, error) {
 	if strings.TrimSpace(interval) == "" {
 		interval = DefaultRefreshInterval
 	}
-	duration, err := time.ParseDuration(interval)
+	parsed, err := schedule.Parse(interval)
 	if err != nil {
-		return 0, fmt.Errorf("invalid refresh interval %q: %w", interval, err)
+		return nil, fmt.Errorf("invalid refresh interval %q: %w", interval, err)
+	}
+	return parsed, nil
+}
+
+func parseRefreshJitter(jitter string) (time.Duration, error) {
+	if strings.TrimSpace(jitter) == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(jitter)
+	if err != nil {
+		return 0, fmt.Errorf("invalid refresh jitter %q: %w", jitter, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("invalid refresh jitter %q: must not be negative", jitter)
 	}
 	return duration, nil
 }
@@ -150,15 +314,23 @@ type LoggingConfig struct {
 
 // Validate ensures logging settings are valid.
 func (l LoggingConfig) Validate() error {
+	if problems := l.problems(); len(problems) > 0 {
+		return problems[0].validationError()
+	}
+	return nil
+}
+
+func (l LoggingConfig) problems() []Problem {
+	var problems []Problem
 	switch l.LogDiffs {
 	case "", LogDiffsDisabled, LogDiffsCompact, LogDiffsDetailed:
 	default:
-		return fmt.Errorf("unsupported diff logging mode %q", l.LogDiffs)
+		problems = append(problems, Problem{Path: "logDiffs", Err: fmt.Errorf("unsupported diff logging mode %q", l.LogDiffs)})
 	}
 	if err := l.OTLP.Validate(); err != nil {
-		return fmt.Errorf("validate otlp logging config: %w", err)
+		problems = append(problems, Problem{Path: "otlp", Err: err, context: "validate otlp logging config"})
 	}
-	return nil
+	return problems
 }
 
 // LogDiffMode enumerates supported diff logging modes.
@@ -245,17 +417,130 @@ func (c OTLPConfig) Validate() error {
 	return nil
 }
 
+// IsWildcard reports whether the rule expands to every resource in an API group or group version.
+func (rule *ObjectRule) IsWildcard() bool {
+	return rule.Kind == WildcardKind
+}
+
+// HasWildcardRules reports whether any object rule needs to be expanded through API discovery.
+func (cfg *Config) HasWildcardRules() bool {
+	for i := range cfg.Objects {
+		if cfg.Objects[i].IsWildcard() {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate ensures an object rule is internally consistent.
 func (rule *ObjectRule) Validate() error {
+	if problems := rule.problems(); len(problems) > 0 {
+		return problems[0].Err
+	}
+	return nil
+}
+
+// problems checks every field of the rule, compiling its name patterns when they are valid.
+func (rule *ObjectRule) problems() []Problem {
+	var problems []Problem
+	add := func(path string, err error) {
+		problems = append(problems, Problem{Path: path, Err: err})
+	}
+
+	if path, err := rule.validateWildcard(); err != nil {
+		add(path, err)
+	}
 	if err := checkForDuplicates(rule.Namespaces); err != nil {
-		return err
+		add("namespaces", err)
+	}
+	if err := validateNamespacePatterns(rule.Namespaces); err != nil {
+		add("namespaces", err)
+	}
+	if names, path, err := rule.compileNamePatterns(); err != nil {
+		add(path, err)
+	} else {
+		rule.names = names
 	}
-	if strings.TrimSpace(rule.NamePattern) != "" {
-		if _, err := regexp.Compile(rule.NamePattern); err != nil {
-			return fmt.Errorf("invalid namePattern %q: %w", rule.NamePattern, err)
+	if strings.TrimSpace(rule.LabelSelector) != "" {
+		if _, err := labels.Parse(rule.LabelSelector); err != nil {
+			add("labelSelector", fmt.Errorf("invalid labelSelector %q: %w", rule.LabelSelector, err))
 		}
 	}
-	return nil
+	if strings.TrimSpace(rule.FieldSelector) != "" {
+		if _, err := fields.ParseSelector(rule.FieldSelector); err != nil {
+			add("fieldSelector", fmt.Errorf("invalid fieldSelector %q: %w", rule.FieldSelector, err))
+		}
+	}
+	if strings.TrimSpace(rule.NamespaceSelector) != "" {
+		if _, err := labels.Parse(rule.NamespaceSelector); err != nil {
+			add("namespaceSelector", fmt.Errorf("invalid namespaceSelector %q: %w", rule.NamespaceSelector, err))
+		}
+	}
+	if strings.TrimSpace(rule.RefreshInterval) != "" {
+		if _, err := parseRefreshSchedule(rule.RefreshInterval); err != nil {
+			add("refreshInterval", err)
+		}
+	}
+	if _, err := parseRefreshJitter(rule.RefreshJitter); err != nil {
+		add("refreshJitter", err)
+	}
+	if rule.Output != nil {
+		for _, problem := range rule.Output.problems() {
+			problems = append(problems, problem.within("output", ""))
+		}
+	}
+	for _, problem := range filterProblems(rule.Filters) {
+		problems = append(problems, problem.within("filters", ""))
+	}
+	for _, problem := range patchProblems(rule.Patches) {
+		problems = append(problems, problem.within("patches", ""))
+	}
+	return problems
+}
+
+// validateWildcard checks the wildcard settings of the rule, returning the offending field along with the error.
+func (rule *ObjectRule) validateWildcard() (string, error) {
+	if rule.APIVersion == WildcardVersion {
+		return "apiVersion", fmt.Errorf("apiVersion %q must name an API group, such as \"apps/*\"", rule.APIVersion)
+	}
+	wildcardVersion := strings.HasSuffix(rule.APIVersion, "/"+WildcardVersion)
+	if wildcardVersion && !rule.IsWildcard() {
+		return "kind", fmt.Errorf("apiVersion %q with a wildcard version requires kind %q", rule.APIVersion, WildcardKind)
+	}
+	if len(rule.ExcludeKinds) > 0 && !rule.IsWildcard() {
+		return "excludeKinds", fmt.Errorf("excludeKinds requires kind %q", WildcardKind)
+	}
+	return "", nil
+}
+
+// checkForDuplicateRules reports object rules that are identical, which would collect the same objects twice, along
+// with the index of the repeated rule. This usually happens when several configuration files are merged.
+func checkForDuplicateRules(rules []ObjectRule) (int, error) {
+	seen := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		key, err := json.Marshal(rule)
+		if err != nil {
+			return i, fmt.Errorf("compare object rules: %w", err)
+		}
+		if first, ok := seen[string(key)]; ok {
+			return i, fmt.Errorf("duplicate object rule for %s %s: defined at %s and %s",
+				rule.APIVersion, rule.Kind, rules[first].describeLocation(first), rule.describeLocation(i))
+		}
+		seen[string(key)] = i
+	}
+	return 0, nil
+}
+
+// Origin returns the file and line the rule was loaded from, or an empty string when it was not loaded from a file.
+func (r ObjectRule) Origin() string {
+	return r.origin
+}
+
+func (r ObjectRule) describeLocation(index int) string {
+	if r.origin != "" {
+		return fmt.Sprintf("object rule %d (%s)", index+1, r.origin)
+	}
+	return fmt.Sprintf("object rule %d", index+1)
 }
 
 func checkForDuplicates(namespaces []string) error {
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"github.com/grafana/k8s-manifest-tail/internal"
+	"github.com/grafana/k8s-manifest-tail/internal/schedule"
 	"strings"
 )
 
@@ -10,19 +11,97 @@ func (cfg *Config) Describe() string {
 	result := "This configuration will get manifests for:\n"
 	for _, rule := range cfg.Objects {
 		result += fmt.Sprintf("  %s\n", rule.Describe(cfg))
+		result += fmt.Sprintf("    %s\n", describeFilters(cfg.GetFilters(rule)))
+		if len(rule.Patches) > 0 {
+			result += fmt.Sprintf("    %s\n", describePatches(rule.Patches))
+		}
 	}
 	return result
 }
 
 func (rule *ObjectRule) Describe(c *Config) string {
 	pluralKind := pluralizeKind(rule.Kind)
+	if rule.IsWildcard() {
+		pluralKind = describeWildcardKinds(rule)
+	}
 	includedNamespaces := rule.Namespaces
 	if len(includedNamespaces) == 0 {
 		includedNamespaces = c.Namespaces
 	}
-	description := fmt.Sprintf("%s in %s", pluralKind, describeNamespaceScope(includedNamespaces, c.ExcludeNamespaces))
-	if strings.TrimSpace(rule.NamePattern) != "" {
-		description = fmt.Sprintf("%s with names matching %q", description, rule.NamePattern)
+	namespaceSelector := strings.TrimSpace(rule.NamespaceSelector)
+	if namespaceSelector == "" {
+		namespaceSelector = strings.TrimSpace(c.NamespaceSelector)
+	}
+	description := fmt.Sprintf("%s in %s", pluralKind, describeNamespaceScope(includedNamespaces, c.ExcludeNamespaces, namespaceSelector))
+	if patterns := rule.IncludeNamePatterns(); len(patterns) > 0 {
+		description = fmt.Sprintf("%s with names matching %s", description, internal.FormatQuotedList(patterns))
+	}
+	if len(rule.ExcludeNamePatterns) > 0 {
+		description = fmt.Sprintf("%s excluding names matching %s", description, internal.FormatQuotedList(rule.ExcludeNamePatterns))
+	}
+	if strings.TrimSpace(rule.LabelSelector) != "" {
+		description = fmt.Sprintf("%s with labels matching %q", description, rule.LabelSelector)
+	}
+	if strings.TrimSpace(rule.FieldSelector) != "" {
+		description = fmt.Sprintf("%s with fields matching %q", description, rule.FieldSelector)
+	}
+	if interval := strings.TrimSpace(rule.RefreshInterval); interval != "" {
+		description = fmt.Sprintf("%s, %s", description, describeRefresh(interval))
+	}
+	if rule.Output != nil {
+		description = fmt.Sprintf("%s, %s", description, describeOutput(c.GetOutput(*rule)))
+	}
+	return description
+}
+
+func describeFilters(filters []FilterConfig) string {
+	if len(filters) == 0 {
+		return "Filters: none"
+	}
+	described := make([]string, len(filters))
+	for i, filter := range filters {
+		described[i] = filter.Describe()
+	}
+	return "Filters: " + strings.Join(described, " -> ")
+}
+
+func describePatches(patches []PatchConfig) string {
+	described := make([]string, len(patches))
+	for i, patch := range patches {
+		described[i] = patch.Describe()
+	}
+	return "Patches: " + strings.Join(described, " -> ")
+}
+
+func describeOutput(output OutputConfig) string {
+	description := fmt.Sprintf("written to %q as %s", output.Directory, output.Format)
+	if output.PathTemplate != "" && output.PathTemplate != DefaultPathTemplate {
+		description = fmt.Sprintf("%s files laid out as %q", description, output.PathTemplate)
+	}
+	return description
+}
+
+func describeRefresh(interval string) string {
+	refreshSchedule, err := schedule.Parse(interval)
+	switch {
+	case err != nil:
+		return fmt.Sprintf("refreshed on the invalid schedule %q", interval)
+	case refreshSchedule == nil:
+		return "never refreshed after the initial listing"
+	}
+	if _, ok := refreshSchedule.(schedule.Every); ok {
+		return fmt.Sprintf("refreshed every %s", interval)
+	}
+	return fmt.Sprintf("refreshed on the schedule %q", interval)
+}
+
+func describeWildcardKinds(rule *ObjectRule) string {
+	description := fmt.Sprintf("All kinds in %q", rule.APIVersion)
+	if group, ok := strings.CutSuffix(rule.APIVersion, "/"+WildcardVersion); ok {
+		description = fmt.Sprintf("All kinds in the %q API group", group)
+	}
+	if len(rule.ExcludeKinds) > 0 {
+		description = fmt.Sprintf("%s (except %s)", description, internal.FormatQuotedList(rule.ExcludeKinds))
 	}
 	return description
 }
@@ -37,22 +116,71 @@ func pluralizeKind(kind string) string {
 	return kind + "s"
 }
 
-func describeNamespaceScope(included, excluded []string) string {
+func describeNamespaceScope(included, excluded []string, selector string) string {
 	if len(included) == 0 {
+		if selector != "" {
+			description := fmt.Sprintf("namespaces with labels matching %q", selector)
+			if len(excluded) > 0 {
+				description = fmt.Sprintf("%s (excluding %s)", description, describeExcludedNamespaces(excluded))
+			}
+			return description
+		}
 		if len(excluded) == 0 {
 			return "all namespaces"
 		}
-		return fmt.Sprintf("all namespaces except %s", internal.FormatQuotedList(excluded))
+		return fmt.Sprintf("all namespaces except %s", describeExcludedNamespaces(excluded))
 	}
 
-	scope := "namespaces"
-	if len(included) == 1 {
-		scope = "namespace"
+	description := describeIncludedNamespaces(included)
+	if selector != "" {
+		description = fmt.Sprintf("%s with labels matching %q", description, selector)
 	}
-
-	description := fmt.Sprintf("the %s %s", internal.FormatQuotedList(included), scope)
 	if len(excluded) > 0 {
-		description = fmt.Sprintf("%s (excluding %s)", description, internal.FormatQuotedList(excluded))
+		description = fmt.Sprintf("%s (excluding %s)", description, describeExcludedNamespaces(excluded))
 	}
 	return description
 }
+
+func describeIncludedNamespaces(included []string) string {
+	literals, patterns := splitNamespaceEntries(included)
+	var parts []string
+	if len(literals) > 0 {
+		scope := "namespaces"
+		if len(literals) == 1 {
+			scope = "namespace"
+		}
+		parts = append(parts, fmt.Sprintf("the %s %s", internal.FormatQuotedList(literals), scope))
+	}
+	if len(patterns) > 0 {
+		parts = append(parts, fmt.Sprintf("namespaces matching %s", internal.FormatList(patterns)))
+	}
+	return strings.Join(parts, " or ")
+}
+
+func describeExcludedNamespaces(excluded []string) string {
+	literals, patterns := splitNamespaceEntries(excluded)
+	if len(patterns) == 0 {
+		return internal.FormatQuotedList(literals)
+	}
+	description := fmt.Sprintf("namespaces matching %s", internal.FormatList(patterns))
+	if len(literals) == 0 {
+		return description
+	}
+	return fmt.Sprintf("%s or %s", internal.FormatQuotedList(literals), description)
+}
+
+// splitNamespaceEntries separates literal namespace names from patterns, rendering the patterns for display.
+func splitNamespaceEntries(entries []string) ([]string, []string) {
+	var literals, patterns []string
+	for _, entry := range entries {
+		switch {
+		case strings.HasPrefix(entry, RegexNamespacePrefix):
+			patterns = append(patterns, fmt.Sprintf("the regular expression %q", strings.TrimPrefix(entry, RegexNamespacePrefix)))
+		case IsNamespacePattern(entry):
+			patterns = append(patterns, fmt.Sprintf("%q", entry))
+		default:
+			literals = append(literals, entry)
+		}
+	}
+	return literals, patterns
+}
@@ -108,3 +108,300 @@ func TestDescribe_IncludesNamePattern(t *testing.T) {
 	description := cfg.Describe()
 	g.Expect(description).To(gomega.ContainSubstring(`Services in all namespaces with names matching "alloy-.*"`))
 }
+
+func TestDescribe_IncludesLabelSelector(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Pod", LabelSelector: "app.kubernetes.io/part-of=observability"},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring(`Pods in all namespaces with labels matching "app.kubernetes.io/part-of=observability"`))
+}
+
+func TestDescribe_IncludesFieldSelector(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Secret", FieldSelector: "type!=kubernetes.io/service-account-token"},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring(`Secrets in all namespaces with fields matching "type!=kubernetes.io/service-account-token"`))
+}
+
+func TestDescribe_IncludesNamespaceSelector(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		NamespaceSelector: "tenant=true",
+		ExcludeNamespaces: []string{"kube-system"},
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Pod"},
+			{APIVersion: "v1", Kind: "Service", Namespaces: []string{"prod"}, NamespaceSelector: "tier=gold"},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring(`  Pods in namespaces with labels matching "tenant=true" (excluding "kube-system")`))
+	g.Expect(description).To(gomega.ContainSubstring(`  Services in the "prod" namespace with labels matching "tier=gold" (excluding "kube-system")`))
+}
+
+func TestDescribe_NamespacePatterns(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		ExcludeNamespaces: []string{"kube-system", "re:^ci-[0-9]+$"},
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Pod"},
+			{APIVersion: "apps/v1", Kind: "Deployment", Namespaces: []string{"default", "team-*"}},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring(`  Pods in all namespaces except "kube-system" or namespaces matching the regular expression "^ci-[0-9]+$"`))
+	g.Expect(description).To(gomega.ContainSubstring(`  Deployments in the "default" namespace or namespaces matching "team-*" (excluding "kube-system" or namespaces matching the regular expression "^ci-[0-9]+$")`))
+}
+
+func TestDescribe_IncludesMultipleAndExcludedNamePatterns(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Pod", NamePatterns: []string{"^alloy-", "^grafana-"}, ExcludeNamePatterns: []string{"-canary$"}},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring(`Pods in all namespaces with names matching "^alloy-" or "^grafana-" excluding names matching "-canary$"`))
+}
+
+func TestDescribe_WildcardRules(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{
+			{APIVersion: "rbac.authorization.k8s.io/*", Kind: "*"},
+			{APIVersion: "argoproj.io/v1alpha1", Kind: "*", ExcludeKinds: []string{"Workflow"}},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring(`  All kinds in the "rbac.authorization.k8s.io" API group in all namespaces`))
+	g.Expect(description).To(gomega.ContainSubstring(`  All kinds in "argoproj.io/v1alpha1" (except "Workflow") in all namespaces`))
+}
+
+func TestDescribe_RefreshIntervals(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		RefreshInterval: "24h",
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Pod"},
+			{APIVersion: "example.com/v1", Kind: "Widget", RefreshInterval: "1h"},
+			{APIVersion: "v1", Kind: "Secret", RefreshInterval: "0 */6 * * *"},
+			{APIVersion: "v1", Kind: "Event", RefreshInterval: "never"},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring("  Pods in all namespaces\n"))
+	g.Expect(description).To(gomega.ContainSubstring("  Widgets in all namespaces, refreshed every 1h\n"))
+	g.Expect(description).To(gomega.ContainSubstring(`  Secrets in all namespaces, refreshed on the schedule "0 */6 * * *"`))
+	g.Expect(description).To(gomega.ContainSubstring("  Events in all namespaces, never refreshed after the initial listing\n"))
+}
+
+func TestDescribe_OutputOverrides(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Output: OutputConfig{Directory: "output", Format: OutputFormatYAML},
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Pod"},
+			{APIVersion: "v1", Kind: "Secret", Output: &OutputConfig{Format: OutputFormatJSON}},
+			{APIVersion: "apps/v1", Kind: "Deployment", Output: &OutputConfig{Directory: "apps", PathTemplate: "{namespace}/{kind}/{name}"}},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring("  Pods in all namespaces\n"))
+	g.Expect(description).To(gomega.ContainSubstring(`  Secrets in all namespaces, written to "output" as json` + "\n"))
+	g.Expect(description).To(gomega.ContainSubstring(`  Deployments in all namespaces, written to "apps" as yaml files laid out as "{namespace}/{kind}/{name}"`))
+}
+
+func TestDescribe_FilterChains(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Pod"},
+			{APIVersion: "autoscaling/v2", Kind: "HorizontalPodAutoscaler", Filters: []FilterConfig{
+				{Name: FilterRedactEnvValues, Replacement: "***"},
+				{Name: FilterRemoveMetadataFields, Fields: []string{"uid"}},
+			}},
+			{APIVersion: "v1", Kind: "ConfigMap", Filters: []FilterConfig{}},
+			{APIVersion: "apps/*", Kind: "*", Filters: []FilterConfig{
+				{Name: FilterRemoveFields, Paths: []string{"spec.revisionHistoryLimit"}, Kinds: []string{"Deployment", "StatefulSet"}},
+			}},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring("  Pods in all namespaces\n    Filters: removeStatus -> removeMetadataFields -> redactEnvValues -> redactData\n"))
+	g.Expect(description).To(gomega.ContainSubstring(`    Filters: redactEnvValues (with "***") -> removeMetadataFields ("uid")` + "\n"))
+	g.Expect(description).To(gomega.ContainSubstring("  ConfigMaps in all namespaces\n    Filters: none\n"))
+	g.Expect(description).To(gomega.ContainSubstring(`    Filters: removeFields ("spec.revisionHistoryLimit") on "Deployment" or "StatefulSet"` + "\n"))
+}
+
+func TestDescribe_RedactDataFilter(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterRedactData, KeyPatterns: []string{"password"}, Hash: &HashConfig{KeyEnv: "REDACTION_KEY"}},
+		},
+		Objects: []ObjectRule{{APIVersion: "v1", Kind: "ConfigMap"}},
+	}
+
+	g.Expect(cfg.Describe()).To(gomega.ContainSubstring(`    Filters: redactData (ConfigMap keys matching "password") hashed with the key in $REDACTION_KEY` + "\n"))
+}
+
+func TestDescribe_NeatFilter(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterNeat, Rules: map[string][]string{"Service": {"spec.ports[*].nodePort"}, "*": {"metadata.labels"}}},
+		},
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Service"},
+			{APIVersion: "v1", Kind: "Pod", Filters: []FilterConfig{{Name: FilterNeat, RuleSet: "v1"}}},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring(`    Filters: neat (rule set ` + LatestNeatRuleSet + `) extended for "*", "Service"` + "\n"))
+	g.Expect(description).To(gomega.ContainSubstring("    Filters: neat (rule set v1)\n"))
+}
+
+func TestDescribe_PruneDefaultsFilter(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{
+			{APIVersion: "apps/v1", Kind: "Deployment", Filters: []FilterConfig{{Name: FilterPruneDefaults}}},
+			{APIVersion: "v1", Kind: "Pod", Filters: []FilterConfig{{Name: FilterPruneDefaults, CacheDirectory: "/var/cache/openapi"}}},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring("    Filters: pruneDefaults\n"))
+	g.Expect(description).To(gomega.ContainSubstring(`    Filters: pruneDefaults (OpenAPI documents cached in "/var/cache/openapi")` + "\n"))
+}
+
+func TestDescribe_RedactEnvValuesContainerPaths(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterRedactEnvValues, ContainerPaths: map[string][]string{"Task": {"spec.steps"}, "*": {"spec.workers"}}},
+		},
+		Objects: []ObjectRule{{APIVersion: "tekton.dev/v1", Kind: "Task"}},
+	}
+
+	g.Expect(cfg.Describe()).To(gomega.ContainSubstring(`    Filters: redactEnvValues (also containers of "*", "Task")` + "\n"))
+}
+
+func TestDescribe_ScrubSecretsFilter(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "ConfigMap", Filters: []FilterConfig{{Name: FilterScrubSecrets}}},
+			{APIVersion: "v1", Kind: "Pod", Filters: []FilterConfig{{Name: FilterScrubSecrets, Detectors: []string{"jwt"}, Patterns: []string{`ticket-\d+`}}}},
+			{APIVersion: "v1", Kind: "Service", Filters: []FilterConfig{{Name: FilterScrubSecrets, Detectors: []string{}, Replacement: "***"}}},
+		},
+	}
+
+	description := cfg.Describe()
+	g.Expect(description).To(gomega.ContainSubstring("    Filters: scrubSecrets (every detector)\n"))
+	g.Expect(description).To(gomega.ContainSubstring(`    Filters: scrubSecrets (detectors "jwt" and patterns "ticket-\\d+")` + "\n"))
+	g.Expect(description).To(gomega.ContainSubstring(`    Filters: scrubSecrets (no patterns) (with "***")` + "\n"))
+}
+
+func TestDescribe_JQFilter(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{
+			{APIVersion: "apps/v1", Kind: "Deployment", Filters: []FilterConfig{{Name: FilterJQ, Program: `del(.spec.replicas)`}}},
+		},
+	}
+
+	g.Expect(cfg.Describe()).To(gomega.ContainSubstring(`    Filters: jq ("del(.spec.replicas)")` + "\n"))
+}
+
+func TestDescribe_Patches(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{{
+			APIVersion: "apps/*",
+			Kind:       "*",
+			Patches: []PatchConfig{
+				{JSON: `[{op: remove, path: /spec/replicas}]`, Kinds: []string{"Deployment", "StatefulSet"}},
+				{StrategicMerge: `{metadata: {labels: {managed-by: argocd}}}`},
+			},
+		}},
+	}
+
+	g.Expect(cfg.Describe()).To(gomega.ContainSubstring(
+		`    Patches: json (1 operation(s)) on "Deployment" or "StatefulSet" -> strategicMerge` + "\n"))
+}
+
+func TestDescribe_PluginFilter(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "ConfigMap", Filters: []FilterConfig{{Name: FilterPlugin, Command: []string{"python3", "/plugins/redact.py"}, Timeout: "5s"}}},
+		},
+	}
+
+	g.Expect(cfg.Describe()).To(gomega.ContainSubstring("    Filters: plugin (python3 /plugins/redact.py) (timeout 5s)\n"))
+}
+
+func TestDescribe_WasmFilter(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "ConfigMap", Filters: []FilterConfig{{Name: FilterWasm, Modules: []string{"/plugins/redact.wasm"}, MemoryLimit: "16Mi", Timeout: "2s"}}},
+		},
+	}
+
+	g.Expect(cfg.Describe()).To(gomega.ContainSubstring(`    Filters: wasm ("/plugins/redact.wasm") (memory 16Mi, timeout 2s)` + "\n"))
+}
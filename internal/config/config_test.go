@@ -77,6 +77,81 @@ func TestObjectRuleValidateNamePattern(t *testing.T) {
 	g.Expect(err.Error()).To(gomega.ContainSubstring("invalid namePattern"))
 }
 
+func TestObjectRuleValidateLabelSelector(t *testing.T) {
+	t.Parallel()
+
+	g := gomega.NewWithT(t)
+
+	rule := ObjectRule{LabelSelector: "app.kubernetes.io/part-of=observability,tier in (frontend,backend),!legacy"}
+	g.Expect(rule.Validate()).To(gomega.Succeed())
+
+	rule = ObjectRule{LabelSelector: "tier in (frontend"}
+	err := rule.Validate()
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("invalid labelSelector"))
+}
+
+func TestObjectRuleValidateFieldSelector(t *testing.T) {
+	t.Parallel()
+
+	g := gomega.NewWithT(t)
+
+	rule := ObjectRule{FieldSelector: "type!=kubernetes.io/service-account-token"}
+	g.Expect(rule.Validate()).To(gomega.Succeed())
+
+	rule = ObjectRule{FieldSelector: "spec.nodeName"}
+	err := rule.Validate()
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("invalid fieldSelector"))
+}
+
+func TestConfigValidateNamespaceSelector(t *testing.T) {
+	t.Parallel()
+
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{NamespaceSelector: "tenant in (a,b)"}
+	g.Expect(cfg.Validate()).To(gomega.Succeed())
+
+	cfg = &Config{NamespaceSelector: "tenant in (a"}
+	err := cfg.Validate()
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("invalid namespaceSelector"))
+
+	cfg = &Config{Objects: []ObjectRule{{APIVersion: "v1", Kind: "Pod", NamespaceSelector: "=oops"}}}
+	err = cfg.Validate()
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("invalid namespaceSelector"))
+}
+
+func TestObjectRuleValidateWildcards(t *testing.T) {
+	t.Parallel()
+
+	g := gomega.NewWithT(t)
+
+	valid := []ObjectRule{
+		{APIVersion: "rbac.authorization.k8s.io/*", Kind: "*"},
+		{APIVersion: "argoproj.io/v1alpha1", Kind: "*", ExcludeKinds: []string{"Workflow"}},
+		{APIVersion: "v1", Kind: "*", ExcludeKinds: []string{"Secret", "Event"}},
+	}
+	for _, rule := range valid {
+		g.Expect(rule.Validate()).To(gomega.Succeed())
+		g.Expect(rule.IsWildcard()).To(gomega.BeTrue())
+	}
+
+	rule := ObjectRule{APIVersion: "apps/*", Kind: "Deployment"}
+	g.Expect(rule.Validate()).To(gomega.MatchError(`apiVersion "apps/*" with a wildcard version requires kind "*"`))
+
+	rule = ObjectRule{APIVersion: "apps/v1", Kind: "Deployment", ExcludeKinds: []string{"ReplicaSet"}}
+	g.Expect(rule.Validate()).To(gomega.MatchError(`excludeKinds requires kind "*"`))
+
+	rule = ObjectRule{APIVersion: "*", Kind: "*"}
+	g.Expect(rule.Validate()).To(gomega.MatchError(`apiVersion "*" must name an API group, such as "apps/*"`))
+
+	cfg := &Config{Objects: []ObjectRule{{APIVersion: "v1", Kind: "Pod"}, {APIVersion: "apps/*", Kind: "*"}}}
+	g.Expect(cfg.HasWildcardRules()).To(gomega.BeTrue())
+}
+
 func TestConfigValidateInvokesRuleValidation(t *testing.T) {
 	t.Parallel()
 
@@ -93,25 +168,60 @@ func TestConfigValidateInvokesRuleValidation(t *testing.T) {
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 }
 
-func TestGetRefreshInterval(t *testing.T) {
+func TestGetRefreshSchedule(t *testing.T) {
 	t.Parallel()
-
 	g := gomega.NewWithT(t)
 
-	cfg := &Config{}
-	duration, err := cfg.GetRefreshInterval()
+	start := time.Date(2026, time.March, 4, 10, 17, 0, 0, time.UTC)
+	cfg := &Config{RefreshInterval: "6h", RefreshJitter: "5m"}
+
+	inherited, err := cfg.GetRefreshSchedule(ObjectRule{})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
-	g.Expect(duration).To(gomega.Equal(24 * time.Hour))
+	g.Expect(inherited.Next(start)).To(gomega.Equal(start.Add(6 * time.Hour)))
 
-	cfg.RefreshInterval = "2h30m"
-	duration, err = cfg.GetRefreshInterval()
+	hourly, err := cfg.GetRefreshSchedule(ObjectRule{RefreshInterval: "0 * * * *"})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
-	g.Expect(duration).To(gomega.Equal(2*time.Hour + 30*time.Minute))
+	g.Expect(hourly.Next(start)).To(gomega.Equal(time.Date(2026, time.March, 4, 11, 0, 0, 0, time.UTC)))
 
-	cfg.RefreshInterval = "oops"
-	_, err = cfg.GetRefreshInterval()
-	g.Expect(err).To(gomega.HaveOccurred())
-	g.Expect(err.Error()).To(gomega.ContainSubstring("invalid refresh interval"))
+	never, err := cfg.GetRefreshSchedule(ObjectRule{RefreshInterval: "never"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(never).To(gomega.BeNil())
+
+	jitter, err := cfg.GetRefreshJitter(ObjectRule{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(jitter).To(gomega.Equal(5 * time.Minute))
+
+	jitter, err = cfg.GetRefreshJitter(ObjectRule{RefreshJitter: "30s"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(jitter).To(gomega.Equal(30 * time.Second))
+}
+
+func TestValidateRefreshSettings(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		RefreshInterval: "@hourly",
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Pod", RefreshInterval: "never"},
+			{APIVersion: "v1", Kind: "Service", RefreshInterval: "*/10 * * * *", RefreshJitter: "1m"},
+		},
+	}
+	g.Expect(cfg.Validate()).To(gomega.Succeed())
+
+	cfg = &Config{
+		RefreshJitter: "-1m",
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Pod", RefreshInterval: "99 * * * *"},
+			{APIVersion: "v1", Kind: "Service", RefreshJitter: "soon"},
+		},
+	}
+	var paths []string
+	for _, problem := range cfg.Problems() {
+		paths = append(paths, problem.Path)
+	}
+	g.Expect(paths).To(gomega.Equal([]string{"objects[0].refreshInterval", "objects[1].refreshJitter", "refreshJitter"}))
+	g.Expect(cfg.Validate().Error()).To(gomega.HavePrefix(`validate object rule 1: invalid refresh interval "99 * * * *": invalid cron expression`))
 }
 
 func TestApplyEnvOverrides(t *testing.T) {
@@ -124,6 +234,7 @@ func TestApplyEnvOverrides(t *testing.T) {
 	t.Setenv("K8S_MANIFEST_TAIL_REFRESH_INTERVAL", "2h")
 	t.Setenv("K8S_MANIFEST_TAIL_NAMESPACES", "default,prod")
 	t.Setenv("K8S_MANIFEST_TAIL_EXCLUDE_NAMESPACES", "kube-system")
+	t.Setenv("K8S_MANIFEST_TAIL_NAMESPACE_SELECTOR", "tenant=true")
 
 	ApplyEnvOverrides(cfg)
 
@@ -134,6 +245,7 @@ func TestApplyEnvOverrides(t *testing.T) {
 	g.Expect(cfg.RefreshInterval).To(gomega.Equal("2h"))
 	g.Expect(cfg.Namespaces).To(gomega.Equal([]string{"default", "prod"}))
 	g.Expect(cfg.ExcludeNamespaces).To(gomega.Equal([]string{"kube-system"}))
+	g.Expect(cfg.NamespaceSelector).To(gomega.Equal("tenant=true"))
 }
 
 func TestLoggingConfigUnmarshalBool(t *testing.T) {
@@ -199,3 +311,49 @@ func TestOTLPConfigEnabled(t *testing.T) {
 		}
 	})
 }
+
+func TestGetOutputMergesRuleOverrides(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{Output: OutputConfig{Directory: "output", Format: OutputFormatYAML}}
+
+	g.Expect(cfg.GetOutput(ObjectRule{Kind: "Pod"})).To(gomega.Equal(cfg.Output))
+	g.Expect(cfg.GetOutput(ObjectRule{Kind: "Secret", Output: &OutputConfig{Format: OutputFormatJSON}})).To(gomega.Equal(OutputConfig{
+		Directory: "output",
+		Format:    OutputFormatJSON,
+	}))
+	g.Expect(cfg.GetOutput(ObjectRule{Kind: "Deployment", Output: &OutputConfig{Directory: "apps", PathTemplate: "{group}/{kind}/{namespace}/{name}"}})).To(gomega.Equal(OutputConfig{
+		Directory:    "apps",
+		Format:       OutputFormatYAML,
+		PathTemplate: "{group}/{kind}/{namespace}/{name}",
+	}))
+}
+
+func TestOutputOverridesAreValidated(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Output: OutputConfig{Directory: "output", Format: OutputFormatYAML, PathTemplate: "{kind}/{uid}"},
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Pod", Output: &OutputConfig{Format: "xml"}},
+			{APIVersion: "v1", Kind: "Secret", Output: &OutputConfig{PathTemplate: "{kind}"}},
+			{APIVersion: "v1", Kind: "Service", Output: &OutputConfig{PathTemplate: "../{name}"}},
+			{APIVersion: "v1", Kind: "ConfigMap", Output: &OutputConfig{PathTemplate: "{group}/{version}/{name}"}},
+			{APIVersion: "v1", Kind: "Endpoints", Output: &OutputConfig{PathTemplate: "{group}/{version}/{kind}/{namespace}/{name}"}},
+		},
+	}
+
+	var messages []string
+	for _, problem := range cfg.Problems() {
+		messages = append(messages, problem.Path+": "+problem.Err.Error())
+	}
+	g.Expect(messages).To(gomega.ConsistOf(
+		`output.pathTemplate: invalid pathTemplate "{kind}/{uid}": unknown placeholder {uid}`,
+		`objects[0].output.format: invalid output format "xml" (expected yaml or json)`,
+		`objects[1].output.pathTemplate: invalid pathTemplate "{kind}": must contain {kind}, {namespace}, and {name} so that distinct objects do not share a file`,
+		`objects[2].output.pathTemplate: invalid pathTemplate "../{name}": must contain {kind}, {namespace}, and {name} so that distinct objects do not share a file`,
+		`objects[3].output.pathTemplate: invalid pathTemplate "{group}/{version}/{name}": must contain {kind}, {namespace}, and {name} so that distinct objects do not share a file`,
+	))
+}
@@ -0,0 +1,460 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/itchyny/gojq"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/grafana/k8s-manifest-tail/internal"
+	"github.com/grafana/k8s-manifest-tail/internal/fieldpath"
+)
+
+// Built-in filter names.
+const (
+	FilterRemoveStatus         = "removeStatus"
+	FilterRemoveMetadataFields = "removeMetadataFields"
+	FilterRedactEnvValues      = "redactEnvValues"
+	FilterRemoveFields         = "removeFields"
+	FilterRedactData           = "redactData"
+	FilterNeat                 = "neat"
+	FilterPruneDefaults        = "pruneDefaults"
+	FilterScrubSecrets         = "scrubSecrets"
+	FilterJQ                   = "jq"
+	FilterPlugin               = "plugin"
+	FilterWasm                 = "wasm"
+)
+
+// SecretDetectors lists the built-in detectors of scrubSecrets, which recognize common credentials in any string.
+var SecretDetectors = []string{"awsAccessKey", "bearerToken", "githubToken", "jwt", "passwordPair", "privateKey", "slackToken", "urlUserinfo"}
+
+// NeatRuleSets lists the versions of the curated neat rules, oldest first. A version never changes once released, so
+// pinning one keeps the output stable across upgrades.
+var NeatRuleSets = []string{"v1"}
+
+// LatestNeatRuleSet is the neat rule set used when a filter does not pin one.
+var LatestNeatRuleSet = NeatRuleSets[len(NeatRuleSets)-1]
+
+// DefaultRemovedMetadataFields lists the metadata fields removeMetadataFields strips when no fields are configured.
+var DefaultRemovedMetadataFields = []string{"managedFields", "resourceVersion", "uid", "selfLink", "generation", "creationTimestamp"}
+
+// DefaultPluginTimeout bounds each request to a plugin, or each call into a WebAssembly module, when its filter does
+// not set a timeout.
+const DefaultPluginTimeout = 10 * time.Second
+
+// DefaultWasmMemoryLimit caps the memory of each WebAssembly module when its filter does not set a limit.
+var DefaultWasmMemoryLimit = resource.MustParse("64Mi")
+
+// DefaultRedactedValue replaces redacted values when no replacement is configured.
+const DefaultRedactedValue = "<Redacted>"
+
+// DefaultFilters is the filter chain used when the configuration does not declare one.
+var DefaultFilters = []FilterConfig{
+	{Name: FilterRemoveStatus},
+	{Name: FilterRemoveMetadataFields},
+	{Name: FilterRedactEnvValues},
+	{Name: FilterRedactData},
+}
+
+// FilterConfig selects a built-in filter and sets its parameters. Each filter accepts only its own parameters, plus
+// Kinds, which every filter accepts.
+type FilterConfig struct {
+	Name        string      `mapstructure:"name" yaml:"name"`
+	Kinds       []string    `mapstructure:"kinds" yaml:"kinds"`
+	Fields      []string    `mapstructure:"fields" yaml:"fields"`
+	Paths       []string    `mapstructure:"paths" yaml:"paths"`
+	Replacement string      `mapstructure:"replacement" yaml:"replacement"`
+	Hash        *HashConfig `mapstructure:"hash" yaml:"hash"`
+	KeyPatterns []string    `mapstructure:"keyPatterns" yaml:"keyPatterns"`
+	RuleSet     string      `mapstructure:"ruleSet" yaml:"ruleSet"`
+	// Rules maps a kind, or "*" for every kind, to additional field paths to remove.
+	Rules map[string][]string `mapstructure:"rules" yaml:"rules"`
+	// ContainerPaths maps a kind, or "*" for every kind, to the field paths of more containers whose env values
+	// redactEnvValues redacts.
+	ContainerPaths map[string][]string `mapstructure:"containerPaths" yaml:"containerPaths"`
+	// Detectors names the built-in detectors scrubSecrets uses. Unset means every detector; an empty list means none.
+	Detectors []string `mapstructure:"detectors" yaml:"detectors"`
+	// Patterns are more regular expressions whose matches scrubSecrets redacts.
+	Patterns []string `mapstructure:"patterns" yaml:"patterns"`
+	// Program is the jq program that transforms each object.
+	Program string `mapstructure:"program" yaml:"program"`
+	// Command is the program that the plugin filter runs, followed by its arguments.
+	Command []string `mapstructure:"command" yaml:"command"`
+	// Modules are the paths of the WebAssembly modules the wasm filter runs, in order.
+	Modules []string `mapstructure:"modules" yaml:"modules"`
+	// MemoryLimit caps the memory of each WebAssembly module, as a Kubernetes quantity such as "64Mi".
+	MemoryLimit string `mapstructure:"memoryLimit" yaml:"memoryLimit"`
+	// Timeout bounds each request to the plugin, or each call into a WebAssembly module, as a Go duration.
+	Timeout string `mapstructure:"timeout" yaml:"timeout"`
+	// CacheDirectory is where pruneDefaults caches the cluster's OpenAPI documents.
+	CacheDirectory string `mapstructure:"cacheDirectory" yaml:"cacheDirectory"`
+}
+
+// HashConfig makes a redacting filter replace each value with a keyed fingerprint instead of a fixed placeholder, so
+// that changed values still show up as changes. The key comes from exactly one of KeyEnv and KeyFile.
+type HashConfig struct {
+	// KeyEnv names the environment variable holding the HMAC key.
+	KeyEnv string `mapstructure:"keyEnv" yaml:"keyEnv"`
+	// KeyFile is the path of a file holding the HMAC key. Trailing line breaks are ignored.
+	KeyFile string `mapstructure:"keyFile" yaml:"keyFile"`
+}
+
+// filterParameters lists the parameters accepted by each built-in filter, by YAML field name.
+var filterParameters = map[string][]string{
+	FilterRemoveStatus:         nil,
+	FilterRemoveMetadataFields: {"fields"},
+	FilterRedactEnvValues:      append([]string{"containerPaths"}, redactionParameters...),
+	FilterRemoveFields:         {"paths"},
+	FilterRedactData:           append([]string{"keyPatterns"}, redactionParameters...),
+	FilterNeat:                 {"ruleSet", "rules"},
+	FilterPruneDefaults:        {"cacheDirectory"},
+	FilterScrubSecrets:         append([]string{"detectors", "patterns"}, redactionParameters...),
+	FilterJQ:                   {"program"},
+	FilterPlugin:               {"command", "timeout"},
+	FilterWasm:                 {"modules", "memoryLimit", "timeout"},
+}
+
+// redactionParameters lists the parameters of every redacting filter, which choose how values are replaced.
+var redactionParameters = []string{"replacement", "hash"}
+
+// commonFilterParameters lists the parameters every filter accepts.
+var commonFilterParameters = []string{"kinds"}
+
+// FilterNames returns the names of the built-in filters in lexical order.
+func FilterNames() []string {
+	names := make([]string, 0, len(filterParameters))
+	for name := range filterParameters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// GetFilters returns the filter chain of a rule: the rule's own filters when it declares any, otherwise the global
+// filters, otherwise DefaultFilters. An empty list turns filtering off.
+func (cfg *Config) GetFilters(rule ObjectRule) []FilterConfig {
+	if rule.Filters != nil {
+		return rule.Filters
+	}
+	if cfg != nil && cfg.Filters != nil {
+		return cfg.Filters
+	}
+	return DefaultFilters
+}
+
+func filterProblems(filters []FilterConfig) []Problem {
+	var problems []Problem
+	for i, filter := range filters {
+		for _, problem := range filter.problems() {
+			problems = append(problems, problem.within(fmt.Sprintf("[%d]", i), ""))
+		}
+	}
+	return problems
+}
+
+func (f FilterConfig) problems() []Problem {
+	accepted, ok := filterParameters[f.Name]
+	if !ok {
+		return []Problem{{Path: "name", Err: fmt.Errorf("unknown filter %q (expected one of %s)", f.Name, internal.FormatQuotedList(FilterNames()))}}
+	}
+
+	var problems []Problem
+	for _, parameter := range f.parameters() {
+		if !slices.Contains(accepted, parameter) && !slices.Contains(commonFilterParameters, parameter) {
+			problems = append(problems, Problem{Path: parameter, Err: fmt.Errorf("filter %q does not accept %s", f.Name, parameter)})
+		}
+	}
+	for _, field := range f.Fields {
+		if strings.TrimSpace(field) == "" {
+			problems = append(problems, Problem{Path: "fields", Err: fmt.Errorf("filter %q has an empty metadata field name", f.Name)})
+		}
+	}
+	if f.Name == FilterJQ {
+		if _, err := CompileJQ(f.Program); err != nil {
+			problems = append(problems, Problem{Path: "program", Err: err})
+		}
+	}
+	if f.Name == FilterPlugin && (len(f.Command) == 0 || strings.TrimSpace(f.Command[0]) == "") {
+		problems = append(problems, Problem{Path: "command", Err: fmt.Errorf("filter %q requires a command", f.Name)})
+	}
+	if f.Name == FilterWasm && len(f.Modules) == 0 {
+		problems = append(problems, Problem{Path: "modules", Err: fmt.Errorf("filter %q requires at least one module", f.Name)})
+	}
+	for i, module := range f.Modules {
+		if strings.TrimSpace(module) == "" {
+			problems = append(problems, Problem{Path: fmt.Sprintf("modules[%d]", i), Err: fmt.Errorf("filter %q has an empty module path", f.Name)})
+		}
+	}
+	if _, err := f.EffectiveMemoryLimit(); err != nil {
+		problems = append(problems, Problem{Path: "memoryLimit", Err: err})
+	}
+	if _, err := f.EffectiveTimeout(); err != nil {
+		problems = append(problems, Problem{Path: "timeout", Err: err})
+	}
+	if f.Name == FilterRemoveFields && len(f.Paths) == 0 {
+		problems = append(problems, Problem{Path: "paths", Err: fmt.Errorf("filter %q requires at least one path", f.Name)})
+	}
+	for i, path := range f.Paths {
+		if _, err := fieldpath.Parse(path); err != nil {
+			problems = append(problems, Problem{Path: fmt.Sprintf("paths[%d]", i), Err: err})
+		}
+	}
+	if f.RuleSet != "" && !slices.Contains(NeatRuleSets, f.RuleSet) {
+		problems = append(problems, Problem{Path: "ruleSet", Err: fmt.Errorf("unknown rule set %q (expected one of %s)", f.RuleSet, internal.FormatQuotedList(NeatRuleSets))})
+	}
+	problems = append(problems, pathsByKindProblems("rules", f.Rules)...)
+	problems = append(problems, pathsByKindProblems("containerPaths", f.ContainerPaths)...)
+	for i, pattern := range f.KeyPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			problems = append(problems, Problem{Path: fmt.Sprintf("keyPatterns[%d]", i), Err: fmt.Errorf("invalid key pattern %q: %w", pattern, err)})
+		}
+	}
+	for i, pattern := range f.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			problems = append(problems, Problem{Path: fmt.Sprintf("patterns[%d]", i), Err: fmt.Errorf("invalid pattern %q: %w", pattern, err)})
+		}
+	}
+	for i, detector := range f.Detectors {
+		if !slices.Contains(SecretDetectors, detector) {
+			problems = append(problems, Problem{Path: fmt.Sprintf("detectors[%d]", i), Err: fmt.Errorf("unknown detector %q (expected one of %s)", detector, internal.FormatQuotedList(SecretDetectors))})
+		}
+	}
+	if f.Hash != nil {
+		if f.Replacement != "" {
+			problems = append(problems, Problem{Path: "replacement", Err: fmt.Errorf("filter %q cannot use both a replacement and a hash", f.Name)})
+		}
+		for _, problem := range f.Hash.problems() {
+			problems = append(problems, problem.within("hash", ""))
+		}
+	}
+	return problems
+}
+
+// CompileJQ parses and compiles a jq program.
+func CompileJQ(program string) (*gojq.Code, error) {
+	if strings.TrimSpace(program) == "" {
+		return nil, errors.New("jq program is empty")
+	}
+	query, err := gojq.Parse(program)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq program: %w", err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq program: %w", err)
+	}
+	return code, nil
+}
+
+// EffectiveTimeout returns how long the plugin filter waits for each response, and how long the wasm filter lets each
+// module run: the configured timeout, or DefaultPluginTimeout.
+func (f FilterConfig) EffectiveTimeout() (time.Duration, error) {
+	if strings.TrimSpace(f.Timeout) == "" {
+		return DefaultPluginTimeout, nil
+	}
+	timeout, err := time.ParseDuration(f.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", f.Timeout, err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be positive", f.Timeout)
+	}
+	return timeout, nil
+}
+
+// EffectiveMemoryLimit returns how many bytes of memory each module of the wasm filter may use: the configured limit,
+// or DefaultWasmMemoryLimit.
+func (f FilterConfig) EffectiveMemoryLimit() (int64, error) {
+	if strings.TrimSpace(f.MemoryLimit) == "" {
+		return DefaultWasmMemoryLimit.Value(), nil
+	}
+	limit, err := resource.ParseQuantity(f.MemoryLimit)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory limit %q: %w", f.MemoryLimit, err)
+	}
+	if limit.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid memory limit %q: must be positive", f.MemoryLimit)
+	}
+	return limit.Value(), nil
+}
+
+// pathsByKindProblems checks a parameter that maps kinds to field paths.
+func pathsByKindProblems(parameter string, pathsByKind map[string][]string) []Problem {
+	var problems []Problem
+	for _, kind := range slices.Sorted(maps.Keys(pathsByKind)) {
+		if strings.TrimSpace(kind) == "" {
+			problems = append(problems, Problem{Path: parameter, Err: fmt.Errorf("%s must be keyed by kind or \"*\"", parameter)})
+		}
+		for i, path := range pathsByKind[kind] {
+			if _, err := fieldpath.Parse(path); err != nil {
+				problems = append(problems, Problem{Path: fmt.Sprintf("%s.%s[%d]", parameter, kind, i), Err: err})
+			}
+		}
+	}
+	return problems
+}
+
+func (h HashConfig) problems() []Problem {
+	path := "keyEnv"
+	switch {
+	case h.KeyEnv == "" && h.KeyFile == "":
+		return []Problem{{Err: errors.New("hash requires keyEnv or keyFile, the source of the key")}}
+	case h.KeyEnv != "" && h.KeyFile != "":
+		return []Problem{{Path: "keyFile", Err: errors.New("hash cannot use both keyEnv and keyFile")}}
+	case h.KeyFile != "":
+		path = "keyFile"
+	}
+	if _, err := h.Key(); err != nil {
+		return []Problem{{Path: path, Err: err}}
+	}
+	return nil
+}
+
+// Key reads the HMAC key from its environment variable or file.
+func (h HashConfig) Key() ([]byte, error) {
+	if h.KeyFile != "" {
+		data, err := os.ReadFile(h.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read hash key: %w", err)
+		}
+		key := strings.TrimRight(string(data), "\r\n")
+		if key == "" {
+			return nil, fmt.Errorf("hash key file %s is empty", h.KeyFile)
+		}
+		return []byte(key), nil
+	}
+	key := os.Getenv(h.KeyEnv)
+	if key == "" {
+		return nil, fmt.Errorf("environment variable %s for the hash key is not set", h.KeyEnv)
+	}
+	return []byte(key), nil
+}
+
+// Describe names the source of the key.
+func (h HashConfig) Describe() string {
+	if h.KeyFile != "" {
+		return fmt.Sprintf("hashed with the key in %s", h.KeyFile)
+	}
+	return fmt.Sprintf("hashed with the key in $%s", h.KeyEnv)
+}
+
+// parameters returns the YAML names of the parameters that are set.
+func (f FilterConfig) parameters() []string {
+	var parameters []string
+	value := reflect.ValueOf(f)
+	for i := 0; i < value.NumField(); i++ {
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "name" || value.Field(i).IsZero() {
+			continue
+		}
+		parameters = append(parameters, name)
+	}
+	return parameters
+}
+
+// Describe summarizes the filter and its parameters.
+func (f FilterConfig) Describe() string {
+	description := f.Name
+	switch {
+	case len(f.Fields) > 0:
+		description = fmt.Sprintf("%s (%s)", description, quoteAll(f.Fields))
+	case len(f.Paths) > 0:
+		description = fmt.Sprintf("%s (%s)", description, quoteAll(f.Paths))
+	case len(f.KeyPatterns) > 0:
+		description = fmt.Sprintf("%s (ConfigMap keys matching %s)", description, quoteAll(f.KeyPatterns))
+	case f.Name == FilterNeat:
+		description = fmt.Sprintf("%s (rule set %s)", description, f.EffectiveRuleSet())
+		if len(f.Rules) > 0 {
+			description = fmt.Sprintf("%s extended for %s", description, quoteAll(slices.Sorted(maps.Keys(f.Rules))))
+		}
+	case len(f.ContainerPaths) > 0:
+		description = fmt.Sprintf("%s (also containers of %s)", description, quoteAll(slices.Sorted(maps.Keys(f.ContainerPaths))))
+	case f.Program != "":
+		description = fmt.Sprintf("%s (%q)", description, f.Program)
+	case len(f.Command) > 0:
+		description = fmt.Sprintf("%s (%s)", description, strings.Join(f.Command, " "))
+		if f.Timeout != "" {
+			description = fmt.Sprintf("%s (timeout %s)", description, f.Timeout)
+		}
+	case len(f.Modules) > 0:
+		description = fmt.Sprintf("%s (%s)", description, quoteAll(f.Modules))
+		var limits []string
+		if f.MemoryLimit != "" {
+			limits = append(limits, "memory "+f.MemoryLimit)
+		}
+		if f.Timeout != "" {
+			limits = append(limits, "timeout "+f.Timeout)
+		}
+		if len(limits) > 0 {
+			description = fmt.Sprintf("%s (%s)", description, strings.Join(limits, ", "))
+		}
+	case f.Name == FilterScrubSecrets:
+		description = fmt.Sprintf("%s (%s)", description, f.describeDetectors())
+	case f.CacheDirectory != "":
+		description = fmt.Sprintf("%s (OpenAPI documents cached in %q)", description, f.CacheDirectory)
+	}
+	switch {
+	case f.Hash != nil:
+		description = fmt.Sprintf("%s %s", description, f.Hash.Describe())
+	case f.Replacement != "":
+		description = fmt.Sprintf("%s (with %q)", description, f.Replacement)
+	}
+	if len(f.Kinds) > 0 {
+		description = fmt.Sprintf("%s on %s", description, internal.FormatQuotedList(f.Kinds))
+	}
+	return description
+}
+
+func (f FilterConfig) describeDetectors() string {
+	var parts []string
+	switch {
+	case f.Detectors == nil:
+		parts = append(parts, "every detector")
+	case len(f.Detectors) > 0:
+		parts = append(parts, "detectors "+quoteAll(f.Detectors))
+	}
+	if len(f.Patterns) > 0 {
+		parts = append(parts, "patterns "+quoteAll(f.Patterns))
+	}
+	if len(parts) == 0 {
+		return "no patterns"
+	}
+	return strings.Join(parts, " and ")
+}
+
+// EffectiveDetectors returns the built-in detectors scrubSecrets uses.
+func (f FilterConfig) EffectiveDetectors() []string {
+	if f.Detectors == nil {
+		return SecretDetectors
+	}
+	return f.Detectors
+}
+
+// EffectiveRuleSet returns the pinned neat rule set, or the latest one.
+func (f FilterConfig) EffectiveRuleSet() string {
+	if f.RuleSet == "" {
+		return LatestNeatRuleSet
+	}
+	return f.RuleSet
+}
+
+// AppliesTo reports whether the filter applies to objects of the supplied kind.
+func (f FilterConfig) AppliesTo(kind string) bool {
+	return len(f.Kinds) == 0 || slices.Contains(f.Kinds, kind)
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,318 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/grafana/k8s-manifest-tail/internal"
+)
+
+func TestGetFiltersPrefersRuleThenGlobalThenDefaults(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	pods := ObjectRule{APIVersion: "v1", Kind: "Pod"}
+	autoscalers := ObjectRule{APIVersion: "autoscaling/v2", Kind: "HorizontalPodAutoscaler", Filters: []FilterConfig{
+		{Name: FilterRemoveMetadataFields},
+	}}
+	unfiltered := ObjectRule{APIVersion: "v1", Kind: "ConfigMap", Filters: []FilterConfig{}}
+
+	cfg := &Config{}
+	g.Expect(cfg.GetFilters(pods)).To(gomega.Equal(DefaultFilters))
+	g.Expect(cfg.GetFilters(autoscalers)).To(gomega.Equal(autoscalers.Filters))
+	g.Expect(cfg.GetFilters(unfiltered)).To(gomega.BeEmpty())
+
+	cfg.Filters = []FilterConfig{{Name: FilterRemoveStatus}}
+	g.Expect(cfg.GetFilters(pods)).To(gomega.Equal(cfg.Filters))
+	g.Expect(cfg.GetFilters(autoscalers)).To(gomega.Equal(autoscalers.Filters))
+
+	cfg.Filters = []FilterConfig{}
+	g.Expect(cfg.GetFilters(pods)).To(gomega.BeEmpty())
+}
+
+func TestFiltersAreValidated(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterRemoveStatus},
+			{Name: "removeEverything"},
+		},
+		Objects: []ObjectRule{
+			{APIVersion: "v1", Kind: "Pod", Filters: []FilterConfig{
+				{Name: FilterRemoveStatus, Fields: []string{"phase"}},
+				{Name: FilterRemoveMetadataFields, Fields: []string{"uid", " "}},
+				{Name: FilterRedactEnvValues, Replacement: "***", Kinds: []string{"Pod"}},
+				{Name: FilterRemoveFields},
+				{Name: FilterRemoveFields, Paths: []string{"metadata.labels", "spec.containers[0"}},
+			}},
+		},
+	}
+
+	var messages []string
+	for _, problem := range cfg.Problems() {
+		messages = append(messages, problem.Path+": "+problem.Err.Error())
+	}
+	g.Expect(messages).To(gomega.ConsistOf(
+		`filters[1].name: unknown filter "removeEverything" (expected one of `+internal.FormatQuotedList(FilterNames())+`)`,
+		`objects[0].filters[0].fields: filter "removeStatus" does not accept fields`,
+		`objects[0].filters[1].fields: filter "removeMetadataFields" has an empty metadata field name`,
+		`objects[0].filters[3].paths: filter "removeFields" requires at least one path`,
+		`objects[0].filters[4].paths[1]: invalid field path "spec.containers[0": missing "]" at offset 17`,
+	))
+}
+
+func TestLoadFilters(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	path := writeConfig(t, filepath.Join(t.TempDir(), "config.yaml"), `
+filters:
+  - name: removeStatus
+objects:
+  - apiVersion: autoscaling/v2
+    kind: HorizontalPodAutoscaler
+    filters:
+      - name: removeMetadataFields
+        fields: [managedFields]
+      - name: unknown
+`)
+
+	cfg, err := Load(path)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cfg.Filters).To(gomega.Equal([]FilterConfig{{Name: FilterRemoveStatus}}))
+	g.Expect(cfg.Objects[0].Filters[0]).To(gomega.Equal(FilterConfig{Name: FilterRemoveMetadataFields, Fields: []string{"managedFields"}}))
+
+	problems := cfg.Problems()
+	g.Expect(problems).To(gomega.HaveLen(1))
+	g.Expect(problems[0].Path).To(gomega.Equal("objects[0].filters[1].name"))
+	g.Expect(problems[0].Position.Line).To(gomega.Equal(10))
+}
+
+func TestRedactionFiltersAreValidated(t *testing.T) {
+	t.Setenv("REDACTION_KEY", "salt")
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	keyFile := writeConfig(t, filepath.Join(dir, "key"), "salt\n")
+	emptyKeyFile := writeConfig(t, filepath.Join(dir, "empty"), "\n")
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterRedactData, KeyPatterns: []string{"password", "("}},
+			{Name: FilterRedactData, Hash: &HashConfig{KeyEnv: "REDACTION_KEY"}},
+			{Name: FilterRedactData, Hash: &HashConfig{KeyEnv: "REDACTION_KEY"}, Replacement: "***"},
+			{Name: FilterRedactData, Hash: &HashConfig{}},
+			{Name: FilterRedactData, Hash: &HashConfig{KeyEnv: "MISSING_REDACTION_KEY"}},
+			{Name: FilterRedactEnvValues, KeyPatterns: []string{"password"}},
+			{Name: FilterRedactEnvValues, Hash: &HashConfig{KeyFile: keyFile}},
+			{Name: FilterRedactEnvValues, Hash: &HashConfig{KeyFile: emptyKeyFile}},
+			{Name: FilterRedactEnvValues, Hash: &HashConfig{KeyFile: keyFile, KeyEnv: "REDACTION_KEY"}},
+		},
+	}
+
+	var messages []string
+	for _, problem := range cfg.Problems() {
+		messages = append(messages, problem.Path+": "+problem.Err.Error())
+	}
+	g.Expect(messages).To(gomega.ConsistOf(
+		"filters[0].keyPatterns[1]: invalid key pattern \"(\": error parsing regexp: missing closing ): `(`",
+		`filters[2].replacement: filter "redactData" cannot use both a replacement and a hash`,
+		`filters[3].hash: hash requires keyEnv or keyFile, the source of the key`,
+		`filters[4].hash.keyEnv: environment variable MISSING_REDACTION_KEY for the hash key is not set`,
+		`filters[5].keyPatterns: filter "redactEnvValues" does not accept keyPatterns`,
+		`filters[7].hash.keyFile: hash key file `+emptyKeyFile+` is empty`,
+		`filters[8].hash.keyFile: hash cannot use both keyEnv and keyFile`,
+	))
+}
+
+func TestHashConfigKey(t *testing.T) {
+	t.Setenv("REDACTION_KEY", "from-env")
+	g := gomega.NewWithT(t)
+
+	key, err := HashConfig{KeyEnv: "REDACTION_KEY"}.Key()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(key)).To(gomega.Equal("from-env"))
+
+	path := writeConfig(t, filepath.Join(t.TempDir(), "key"), "from-file\r\n")
+	key, err = HashConfig{KeyFile: path}.Key()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(key)).To(gomega.Equal("from-file"))
+
+	_, err = HashConfig{KeyFile: filepath.Join(t.TempDir(), "missing")}.Key()
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("read hash key: open")))
+}
+
+func TestNeatFilterIsValidated(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterNeat},
+			{Name: FilterNeat, RuleSet: LatestNeatRuleSet, Rules: map[string][]string{"Service": {"spec.ports[*].nodePort"}}},
+			{Name: FilterNeat, RuleSet: "v0"},
+			{Name: FilterNeat, Rules: map[string][]string{"*": {"metadata.labels", "spec["}, "": {"spec"}}},
+		},
+	}
+
+	var messages []string
+	for _, problem := range cfg.Problems() {
+		messages = append(messages, problem.Path+": "+problem.Err.Error())
+	}
+	g.Expect(messages).To(gomega.ConsistOf(
+		`filters[2].ruleSet: unknown rule set "v0" (expected one of `+internal.FormatQuotedList(NeatRuleSets)+`)`,
+		`filters[3].rules: rules must be keyed by kind or "*"`,
+		`filters[3].rules.*[1]: invalid field path "spec[": expected an index, a quoted key, "*", or a filter at offset 5`,
+	))
+}
+
+func TestPruneDefaultsFilterIsValidated(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterPruneDefaults, CacheDirectory: "/var/cache/openapi"},
+			{Name: FilterNeat, CacheDirectory: "/var/cache/openapi"},
+		},
+	}
+
+	var messages []string
+	for _, problem := range cfg.Problems() {
+		messages = append(messages, problem.Path+": "+problem.Err.Error())
+	}
+	g.Expect(messages).To(gomega.ConsistOf(
+		`filters[1].cacheDirectory: filter "neat" does not accept cacheDirectory`,
+	))
+}
+
+func TestRedactEnvValuesContainerPathsAreValidated(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterRedactEnvValues, ContainerPaths: map[string][]string{"Task": {"spec.steps"}, "*": {"spec.sidecars[*]"}}},
+			{Name: FilterRedactEnvValues, ContainerPaths: map[string][]string{"Task": {"spec.steps["}, " ": {"spec"}}},
+			{Name: FilterRedactData, ContainerPaths: map[string][]string{"Task": {"spec.steps"}}},
+		},
+	}
+
+	var messages []string
+	for _, problem := range cfg.Problems() {
+		messages = append(messages, problem.Path+": "+problem.Err.Error())
+	}
+	g.Expect(messages).To(gomega.ConsistOf(
+		`filters[1].containerPaths: containerPaths must be keyed by kind or "*"`,
+		`filters[1].containerPaths.Task[0]: invalid field path "spec.steps[": expected an index, a quoted key, "*", or a filter at offset 11`,
+		`filters[2].containerPaths: filter "redactData" does not accept containerPaths`,
+	))
+}
+
+func TestScrubSecretsFilterIsValidated(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterScrubSecrets, Detectors: []string{"jwt"}, Patterns: []string{`token=(\S+)`}},
+			{Name: FilterScrubSecrets, Detectors: []string{"jwt", "magic"}, Patterns: []string{"("}},
+		},
+	}
+
+	var messages []string
+	for _, problem := range cfg.Problems() {
+		messages = append(messages, problem.Path+": "+problem.Err.Error())
+	}
+	g.Expect(messages).To(gomega.ConsistOf(
+		`filters[1].patterns[0]: invalid pattern "(": error parsing regexp: missing closing ): `+"`(`",
+		`filters[1].detectors[1]: unknown detector "magic" (expected one of `+internal.FormatQuotedList(SecretDetectors)+`)`,
+	))
+	g.Expect(FilterConfig{Name: FilterScrubSecrets}.EffectiveDetectors()).To(gomega.Equal(SecretDetectors))
+	g.Expect(FilterConfig{Name: FilterScrubSecrets, Detectors: []string{}}.EffectiveDetectors()).To(gomega.BeEmpty())
+}
+
+func TestJQFilterIsValidated(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterJQ, Program: `del(.spec.replicas)`},
+			{Name: FilterJQ},
+			{Name: FilterJQ, Program: `.metadata |`},
+			{Name: FilterRemoveStatus, Program: `.`},
+		},
+	}
+
+	var messages []string
+	for _, problem := range cfg.Problems() {
+		messages = append(messages, problem.Path+": "+problem.Err.Error())
+	}
+	g.Expect(messages).To(gomega.ConsistOf(
+		`filters[1].program: jq program is empty`,
+		gomega.HavePrefix(`filters[2].program: invalid jq program: `),
+		`filters[3].program: filter "removeStatus" does not accept program`,
+	))
+}
+
+func TestPluginFilterIsValidated(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterPlugin, Command: []string{"python3", "/plugins/redact.py"}, Timeout: "5s"},
+			{Name: FilterPlugin},
+			{Name: FilterPlugin, Command: []string{"redact"}, Timeout: "soon"},
+			{Name: FilterPlugin, Command: []string{"redact"}, Timeout: "-1s"},
+			{Name: FilterJQ, Program: ".", Timeout: "5s"},
+		},
+	}
+
+	var messages []string
+	for _, problem := range cfg.Problems() {
+		messages = append(messages, problem.Path+": "+problem.Err.Error())
+	}
+	g.Expect(messages).To(gomega.ConsistOf(
+		`filters[1].command: filter "plugin" requires a command`,
+		`filters[2].timeout: invalid timeout "soon": time: invalid duration "soon"`,
+		`filters[3].timeout: invalid timeout "-1s": must be positive`,
+		`filters[4].timeout: filter "jq" does not accept timeout`,
+	))
+	g.Expect(FilterConfig{Name: FilterPlugin}.EffectiveTimeout()).To(gomega.Equal(DefaultPluginTimeout))
+}
+
+func TestWasmFilterIsValidated(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Filters: []FilterConfig{
+			{Name: FilterWasm, Modules: []string{"/plugins/redact.wasm"}, MemoryLimit: "16Mi", Timeout: "2s"},
+			{Name: FilterWasm},
+			{Name: FilterWasm, Modules: []string{" "}},
+			{Name: FilterWasm, Modules: []string{"redact.wasm"}, MemoryLimit: "lots"},
+			{Name: FilterWasm, Modules: []string{"redact.wasm"}, MemoryLimit: "0"},
+			{Name: FilterPlugin, Command: []string{"redact"}, MemoryLimit: "16Mi"},
+		},
+	}
+
+	var messages []string
+	for _, problem := range cfg.Problems() {
+		messages = append(messages, problem.Path+": "+problem.Err.Error())
+	}
+	g.Expect(messages).To(gomega.ConsistOf(
+		`filters[1].modules: filter "wasm" requires at least one module`,
+		`filters[2].modules[0]: filter "wasm" has an empty module path`,
+		gomega.HavePrefix(`filters[3].memoryLimit: invalid memory limit "lots": `),
+		`filters[4].memoryLimit: invalid memory limit "0": must be positive`,
+		`filters[5].memoryLimit: filter "plugin" does not accept memoryLimit`,
+	))
+	g.Expect(FilterConfig{Name: FilterWasm}.EffectiveMemoryLimit()).To(gomega.Equal(int64(64 << 20)))
+	g.Expect(FilterConfig{Name: FilterWasm, MemoryLimit: "16Mi"}.EffectiveMemoryLimit()).To(gomega.Equal(int64(16 << 20)))
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// variablePattern matches ${VAR} and ${VAR:-default} references, as well as the $$ escape for a literal dollar sign.
+var variablePattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// interpolateEnv replaces environment variable references in the values of a parsed configuration document.
+func interpolateEnv(node *yaml.Node) error {
+	return interpolateNode(node, os.LookupEnv)
+}
+
+// interpolateNode replaces environment variable references in every scalar value under node. Mapping keys and comments
+// are left untouched, and substituted text never changes the structure of the document. A plain value is resolved
+// again once substituted, so that it may become a boolean or a number. Every reference to an unset variable without a
+// default is reported.
+func interpolateNode(node *yaml.Node, lookup func(string) (string, bool)) error {
+	var errs []error
+	var visit func(node *yaml.Node)
+	visit = func(node *yaml.Node) {
+		switch node.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, child := range node.Content {
+				visit(child)
+			}
+		case yaml.MappingNode:
+			for i := 1; i < len(node.Content); i += 2 {
+				visit(node.Content[i])
+			}
+		case yaml.ScalarNode:
+			value, missing := interpolate(node.Value, lookup)
+			for _, name := range missing {
+				errs = append(errs, fmt.Errorf("line %d: environment variable %q is not set and has no default", node.Line, name))
+			}
+			if value == node.Value {
+				return
+			}
+			node.Value = value
+			if node.Style == 0 {
+				node.Tag = ""
+			}
+		}
+	}
+	visit(node)
+	return errors.Join(errs...)
+}
+
+// interpolate replaces ${VAR} with the value of VAR and ${VAR:-default} with the value of VAR, or the default when VAR
+// is unset or empty. "$$" produces a literal "$". It returns the names of unset variables that have no default, whose
+// references are left as they are.
+func interpolate(value string, lookup func(string) (string, bool)) (string, []string) {
+	var missing []string
+	replaced := variablePattern.ReplaceAllStringFunc(value, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+		groups := variablePattern.FindStringSubmatch(match)
+		name, hasDefault, fallback := groups[1], groups[2] != "", groups[3]
+		resolved, ok := lookup(name)
+		if hasDefault && resolved == "" {
+			return fallback
+		}
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return resolved
+	})
+	return replaced, missing
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+func TestInterpolateNode(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"ENDPOINT": "collector:4317", "EMPTY": "", "ENABLED": "true", "INJECTED": "value # not a comment\nother: field"}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "set variable", input: "endpoint: ${ENDPOINT}", expected: "endpoint: collector:4317"},
+		{name: "set variable ignores default", input: "endpoint: ${ENDPOINT:-localhost:4317}", expected: "endpoint: collector:4317"},
+		{name: "unset variable uses default", input: "namespaces: [\"${NAMESPACE:-default}\"]", expected: "namespaces: [default]"},
+		{name: "empty variable uses default", input: "directory: ${EMPTY:-output}", expected: "directory: output"},
+		{name: "empty default", input: "directory: \"${MISSING:-}\"", expected: "directory: \"\""},
+		{name: "set empty variable", input: "directory: \"${EMPTY}\"", expected: "directory: \"\""},
+		{name: "escaped dollar", input: "namePattern: ^api-$$", expected: "namePattern: ^api-$"},
+		{name: "escaped reference", input: "value: $${ENDPOINT}", expected: "value: ${ENDPOINT}"},
+		{name: "bare dollar untouched", input: "namePattern: ^api-.*$", expected: "namePattern: ^api-.*$"},
+		{name: "comment untouched", input: "  # endpoint: ${MISSING}\nendpoint: x", expected: "endpoint: x"},
+		{name: "trailing comment untouched", input: "endpoint: ${ENDPOINT} # or ${MISSING}", expected: "endpoint: collector:4317"},
+		{name: "keys untouched", input: "${ENDPOINT}: x", expected: "\"${ENDPOINT}\": x"},
+		{name: "plain value resolved again", input: "insecure: ${ENABLED}", expected: "insecure: true"},
+		{name: "quoted value stays a string", input: "insecure: \"${ENABLED}\"", expected: "insecure: \"true\""},
+		{name: "value cannot inject structure", input: "endpoint: ${INJECTED}", expected: "endpoint: \"value # not a comment\\nother: field\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewWithT(t)
+
+			var doc yaml.Node
+			g.Expect(yaml.Unmarshal([]byte(tt.input), &doc)).To(gomega.Succeed())
+			g.Expect(interpolateNode(&doc, lookup)).To(gomega.Succeed())
+
+			var actual, expected map[string]interface{}
+			g.Expect(doc.Decode(&actual)).To(gomega.Succeed())
+			g.Expect(yaml.Unmarshal([]byte(tt.expected), &expected)).To(gomega.Succeed())
+			g.Expect(actual).To(gomega.Equal(expected))
+		})
+	}
+}
+
+func TestInterpolateNodeReportsUnsetVariables(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	var doc yaml.Node
+	g.Expect(yaml.Unmarshal([]byte("output:\n  directory: ${OUTPUT_DIR}\nnamespaces: [\"${NAMESPACE}\"]\n"), &doc)).To(gomega.Succeed())
+
+	lookup := func(string) (string, bool) { return "", false }
+	err := interpolateNode(&doc, lookup)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal(
+		"line 2: environment variable \"OUTPUT_DIR\" is not set and has no default\n" +
+			"line 3: environment variable \"NAMESPACE\" is not set and has no default",
+	))
+}
+
+func TestLoadInterpolatesEnvironment(t *testing.T) {
+	g := gomega.NewWithT(t)
+	t.Setenv("MANIFEST_TAIL_TEST_ENDPOINT", "collector:4317")
+
+	path := writeConfig(t, filepath.Join(t.TempDir(), "config.yaml"), `
+logging:
+  otlp:
+    endpoint: ${MANIFEST_TAIL_TEST_ENDPOINT}
+namespaces: ["${MANIFEST_TAIL_TEST_NAMESPACE:-default}"] # or ${MANIFEST_TAIL_TEST_UNSET}
+objects:
+  - apiVersion: v1
+    kind: Pod
+`)
+
+	cfg, err := Load(path)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cfg.Logging.OTLP.Endpoint).To(gomega.Equal("collector:4317"))
+	g.Expect(cfg.Namespaces).To(gomega.Equal([]string{"default"}))
+}
+
+func TestLoadFailsOnUnsetVariable(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	path := writeConfig(t, filepath.Join(t.TempDir(), "config.yaml"), "refreshInterval: ${MANIFEST_TAIL_TEST_UNSET}\n")
+
+	_, err := Load(path)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal(
+		"interpolate config " + path + ": line 1: environment variable \"MANIFEST_TAIL_TEST_UNSET\" is not set and has no default",
+	))
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// nameMatcher holds the compiled include and exclude name patterns of an ObjectRule.
+type nameMatcher struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func (m *nameMatcher) matches(name string) bool {
+	if len(m.include) > 0 {
+		included := false
+		for _, re := range m.include {
+			if re.MatchString(name) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, re := range m.exclude {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// IncludeNamePatterns returns every include name pattern, combining namePattern and namePatterns.
+func (rule *ObjectRule) IncludeNamePatterns() []string {
+	var patterns []string
+	if strings.TrimSpace(rule.NamePattern) != "" {
+		patterns = append(patterns, rule.NamePattern)
+	}
+	for _, pattern := range rule.NamePatterns {
+		if strings.TrimSpace(pattern) != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// MatchesName reports whether an object name passes the rule's include and exclude name patterns. The patterns are
+// compiled by Validate; rules that have not been validated compile them on each call.
+func (rule *ObjectRule) MatchesName(name string) bool {
+	matcher := rule.names
+	if matcher == nil {
+		var err error
+		if matcher, _, err = rule.compileNamePatterns(); err != nil {
+			return false
+		}
+	}
+	return matcher.matches(name)
+}
+
+// compileNamePatterns compiles the rule's name patterns, returning the field of the first invalid pattern along with
+// the error.
+func (rule *ObjectRule) compileNamePatterns() (*nameMatcher, string, error) {
+	matcher := &nameMatcher{}
+	include := func(pattern string) error {
+		if strings.TrimSpace(pattern) == "" {
+			return nil
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid namePattern %q: %w", pattern, err)
+		}
+		matcher.include = append(matcher.include, re)
+		return nil
+	}
+	if err := include(rule.NamePattern); err != nil {
+		return nil, "namePattern", err
+	}
+	for i, pattern := range rule.NamePatterns {
+		if err := include(pattern); err != nil {
+			return nil, fmt.Sprintf("namePatterns[%d]", i), err
+		}
+	}
+	for i, pattern := range rule.ExcludeNamePatterns {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Sprintf("excludeNamePatterns[%d]", i), fmt.Errorf("invalid excludeNamePattern %q: %w", pattern, err)
+		}
+		matcher.exclude = append(matcher.exclude, re)
+	}
+	return matcher, "", nil
+}
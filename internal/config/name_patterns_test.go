@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestObjectRuleMatchesName(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	rule := ObjectRule{
+		NamePattern:         "^alloy-",
+		NamePatterns:        []string{"^grafana-"},
+		ExcludeNamePatterns: []string{"-canary$"},
+	}
+	g.Expect(rule.Validate()).To(gomega.Succeed())
+
+	g.Expect(rule.MatchesName("alloy-logs")).To(gomega.BeTrue())
+	g.Expect(rule.MatchesName("grafana-agent")).To(gomega.BeTrue())
+	g.Expect(rule.MatchesName("alloy-logs-canary")).To(gomega.BeFalse())
+	g.Expect(rule.MatchesName("nodeexporter")).To(gomega.BeFalse())
+}
+
+func TestObjectRuleMatchesNameWithOnlyExclusions(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	rule := ObjectRule{ExcludeNamePatterns: []string{"^kube-", "-canary$"}}
+
+	g.Expect(rule.MatchesName("api")).To(gomega.BeTrue())
+	g.Expect(rule.MatchesName("kube-proxy")).To(gomega.BeFalse())
+	g.Expect(rule.MatchesName("api-canary")).To(gomega.BeFalse())
+}
+
+func TestObjectRuleValidateExcludeNamePatterns(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	rule := ObjectRule{ExcludeNamePatterns: []string{"("}}
+	err := rule.Validate()
+
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring(`invalid excludeNamePattern "("`))
+}
+
+func TestConfigValidateCompilesRuleNamePatterns(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{Objects: []ObjectRule{{APIVersion: "v1", Kind: "Pod", NamePatterns: []string{"^api$"}}}}
+	g.Expect(cfg.Validate()).To(gomega.Succeed())
+	g.Expect(cfg.Objects[0].names).NotTo(gomega.BeNil())
+	g.Expect(cfg.Objects[0].names.include).To(gomega.HaveLen(1))
+}
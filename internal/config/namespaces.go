@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RegexNamespacePrefix marks a namespace entry as a regular expression rather than a literal name or glob.
+const RegexNamespacePrefix = "re:"
+
+var namespaceRegexps sync.Map
+
+// IsNamespacePattern reports whether a namespace entry is a glob or regular expression rather than a literal name.
+func IsNamespacePattern(entry string) bool {
+	return strings.HasPrefix(entry, RegexNamespacePrefix) || strings.ContainsAny(entry, "*?[")
+}
+
+// NamespaceMatches reports whether the namespace name matches the entry. Entries may be literal names, globs such as
+// "team-*", or regular expressions prefixed with "re:". Invalid patterns never match.
+func NamespaceMatches(entry, name string) bool {
+	if expression, ok := strings.CutPrefix(entry, RegexNamespacePrefix); ok {
+		re, err := compileNamespaceRegexp(expression)
+		return err == nil && re.MatchString(name)
+	}
+	if IsNamespacePattern(entry) {
+		matched, err := path.Match(entry, name)
+		return err == nil && matched
+	}
+	return entry == name
+}
+
+// NamespaceListMatches reports whether the namespace name matches any entry in the list.
+func NamespaceListMatches(entries []string, name string) bool {
+	for _, entry := range entries {
+		if NamespaceMatches(entry, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasNamespacePatterns reports whether any entry in the list is a glob or regular expression.
+func HasNamespacePatterns(entries []string) bool {
+	for _, entry := range entries {
+		if IsNamespacePattern(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateNamespacePatterns(entries []string) error {
+	for _, entry := range entries {
+		if expression, ok := strings.CutPrefix(entry, RegexNamespacePrefix); ok {
+			if _, err := compileNamespaceRegexp(expression); err != nil {
+				return fmt.Errorf("invalid namespace pattern %q: %w", entry, err)
+			}
+			continue
+		}
+		if IsNamespacePattern(entry) {
+			if _, err := path.Match(entry, ""); err != nil {
+				return fmt.Errorf("invalid namespace pattern %q: %w", entry, err)
+			}
+		}
+	}
+	return nil
+}
+
+func compileNamespaceRegexp(expression string) (*regexp.Regexp, error) {
+	if cached, ok := namespaceRegexps.Load(expression); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	namespaceRegexps.Store(expression, re)
+	return re, nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestNamespaceMatches(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	g.Expect(NamespaceMatches("default", "default")).To(gomega.BeTrue())
+	g.Expect(NamespaceMatches("default", "default-2")).To(gomega.BeFalse())
+	g.Expect(NamespaceMatches("team-*", "team-payments")).To(gomega.BeTrue())
+	g.Expect(NamespaceMatches("team-*", "platform")).To(gomega.BeFalse())
+	g.Expect(NamespaceMatches("team-?", "team-a")).To(gomega.BeTrue())
+	g.Expect(NamespaceMatches("re:^ci-[0-9]+$", "ci-1234")).To(gomega.BeTrue())
+	g.Expect(NamespaceMatches("re:^ci-[0-9]+$", "ci-main")).To(gomega.BeFalse())
+	g.Expect(NamespaceMatches("re:[", "[")).To(gomega.BeFalse())
+}
+
+func TestNamespaceListMatches(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	entries := []string{"kube-system", "ci-*"}
+	g.Expect(NamespaceListMatches(entries, "ci-42")).To(gomega.BeTrue())
+	g.Expect(NamespaceListMatches(entries, "kube-system")).To(gomega.BeTrue())
+	g.Expect(NamespaceListMatches(entries, "default")).To(gomega.BeFalse())
+	g.Expect(HasNamespacePatterns(entries)).To(gomega.BeTrue())
+	g.Expect(HasNamespacePatterns([]string{"default"})).To(gomega.BeFalse())
+}
+
+func TestConfigValidateNamespacePatterns(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Namespaces:        []string{"team-*", "re:^ci-[0-9]+$"},
+		ExcludeNamespaces: []string{"team-[ab]"},
+	}
+	g.Expect(cfg.Validate()).To(gomega.Succeed())
+
+	cfg = &Config{ExcludeNamespaces: []string{"re:("}}
+	err := cfg.Validate()
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring(`global exclusion namespaces: invalid namespace pattern "re:("`))
+
+	rule := ObjectRule{Namespaces: []string{"team-["}}
+	err = rule.Validate()
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring(`invalid namespace pattern "team-["`))
+}
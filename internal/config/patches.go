@@ -0,0 +1,117 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	"sigs.k8s.io/yaml"
+
+	"github.com/grafana/k8s-manifest-tail/internal"
+)
+
+// PatchConfig is a patch applied to the objects of a rule after its filters, written as YAML or JSON. Exactly one of
+// JSON and StrategicMerge is set.
+type PatchConfig struct {
+	// Kinds limits the patch to objects of the listed kinds. Empty means every kind the rule collects.
+	Kinds []string `mapstructure:"kinds" yaml:"kinds"`
+	// JSON is an RFC 6902 JSON patch: a list of operations.
+	JSON string `mapstructure:"json" yaml:"json"`
+	// StrategicMerge is a strategic merge patch: a partial object. Kinds that are not built into Kubernetes are merged
+	// as an RFC 7386 JSON merge patch.
+	StrategicMerge string `mapstructure:"strategicMerge" yaml:"strategicMerge"`
+}
+
+// jsonPatchOperations lists the operations of RFC 6902.
+var jsonPatchOperations = []string{"add", "copy", "move", "remove", "replace", "test"}
+
+// AppliesTo reports whether the patch applies to objects of the supplied kind.
+func (p PatchConfig) AppliesTo(kind string) bool {
+	return len(p.Kinds) == 0 || slices.Contains(p.Kinds, kind)
+}
+
+// IsJSONPatch reports whether the patch is an RFC 6902 JSON patch rather than a strategic merge patch.
+func (p PatchConfig) IsJSONPatch() bool {
+	return strings.TrimSpace(p.JSON) != ""
+}
+
+// Document returns the patch as JSON, checking that it has the shape its type requires.
+func (p PatchConfig) Document() ([]byte, error) {
+	hasJSON, hasStrategicMerge := p.IsJSONPatch(), strings.TrimSpace(p.StrategicMerge) != ""
+	switch {
+	case hasJSON && hasStrategicMerge:
+		return nil, errors.New("set only one of json and strategicMerge")
+	case hasJSON:
+		return jsonPatchDocument(p.JSON)
+	case hasStrategicMerge:
+		return strategicMergeDocument(p.StrategicMerge)
+	default:
+		return nil, errors.New("set one of json and strategicMerge")
+	}
+}
+
+func jsonPatchDocument(text string) ([]byte, error) {
+	document, err := yaml.YAMLToJSON([]byte(text))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %w", err)
+	}
+	patch, err := jsonpatch.DecodePatch(document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: expected a list of operations: %w", err)
+	}
+	for i, operation := range patch {
+		kind := operation.Kind()
+		if !slices.Contains(jsonPatchOperations, kind) {
+			return nil, fmt.Errorf("invalid JSON patch: operation %d: unknown op %q (expected one of %s)", i+1, kind, internal.FormatQuotedList(jsonPatchOperations))
+		}
+		if _, err := operation.Path(); err != nil {
+			return nil, fmt.Errorf("invalid JSON patch: operation %d: missing path", i+1)
+		}
+	}
+	return document, nil
+}
+
+func strategicMergeDocument(text string) ([]byte, error) {
+	var patch map[string]any
+	if err := yaml.Unmarshal([]byte(text), &patch); err != nil {
+		return nil, fmt.Errorf("invalid strategic merge patch: expected a partial object: %w", err)
+	}
+	return yaml.YAMLToJSON([]byte(text))
+}
+
+// patchProblems checks every patch of a rule.
+func patchProblems(patches []PatchConfig) []Problem {
+	var problems []Problem
+	for i, patch := range patches {
+		if _, err := patch.Document(); err != nil {
+			path := fmt.Sprintf("[%d]", i)
+			switch hasStrategicMerge := strings.TrimSpace(patch.StrategicMerge) != ""; {
+			case patch.IsJSONPatch() && !hasStrategicMerge:
+				path += ".json"
+			case hasStrategicMerge && !patch.IsJSONPatch():
+				path += ".strategicMerge"
+			}
+			problems = append(problems, Problem{Path: path, Err: err})
+		}
+	}
+	return problems
+}
+
+// Describe summarizes the patch for Config.Describe.
+func (p PatchConfig) Describe() string {
+	description := "strategicMerge"
+	if p.IsJSONPatch() {
+		description = "json"
+		if document, err := p.Document(); err == nil {
+			if patch, err := jsonpatch.DecodePatch(document); err == nil {
+				description = fmt.Sprintf("json (%d operation(s))", len(patch))
+			}
+		}
+	}
+	if len(p.Kinds) > 0 {
+		description = fmt.Sprintf("%s on %s", description, internal.FormatQuotedList(p.Kinds))
+	}
+	return description
+}
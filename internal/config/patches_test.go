@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestPatchesAreValidated(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{
+		Objects: []ObjectRule{{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Patches: []PatchConfig{
+				{JSON: `[{op: remove, path: /spec/replicas}]`},
+				{StrategicMerge: `{spec: {replicas: null}}`},
+				{},
+				{JSON: `[{op: remove, path: /spec/replicas}]`, StrategicMerge: `{spec: {}}`},
+				{JSON: `{op: remove, path: /spec/replicas}`},
+				{JSON: `[{op: delete, path: /spec/replicas}]`},
+				{JSON: `[{op: remove}]`},
+				{StrategicMerge: `[spec]`},
+			},
+		}},
+	}
+
+	var messages []string
+	for _, problem := range cfg.Problems() {
+		messages = append(messages, problem.Path+": "+problem.Err.Error())
+	}
+	g.Expect(messages).To(gomega.ConsistOf(
+		`objects[0].patches[2]: set one of json and strategicMerge`,
+		`objects[0].patches[3]: set only one of json and strategicMerge`,
+		gomega.HavePrefix(`objects[0].patches[4].json: invalid JSON patch: expected a list of operations: `),
+		`objects[0].patches[5].json: invalid JSON patch: operation 1: unknown op "delete" (expected one of "add", "copy", "move", "remove", "replace", or "test")`,
+		`objects[0].patches[6].json: invalid JSON patch: operation 1: missing path`,
+		gomega.HavePrefix(`objects[0].patches[7].strategicMerge: invalid strategic merge patch: expected a partial object: `),
+	))
+}
+
+func TestPatchDocumentAcceptsYAMLAndJSON(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	document, err := PatchConfig{JSON: "- op: remove\n  path: /spec/replicas\n"}.Document()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(document)).To(gomega.Equal(`[{"op":"remove","path":"/spec/replicas"}]`))
+
+	document, err = PatchConfig{StrategicMerge: `{"spec": {"replicas": null}}`}.Document()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(document)).To(gomega.Equal(`{"spec":{"replicas":null}}`))
+}
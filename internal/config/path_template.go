@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultPathTemplate is the layout of manifest files within the output directory, without the file extension.
+const DefaultPathTemplate = "{kind}/{namespace}/{name}"
+
+// PathTemplatePlaceholders lists the placeholders a pathTemplate may use.
+var PathTemplatePlaceholders = []string{"{group}", "{version}", "{kind}", "{namespace}", "{name}"}
+
+var placeholderPattern = regexp.MustCompile(`\{[^}]*\}`)
+
+func validatePathTemplate(template string) error {
+	for _, placeholder := range placeholderPattern.FindAllString(template, -1) {
+		if !slices.Contains(PathTemplatePlaceholders, placeholder) {
+			return fmt.Errorf("invalid pathTemplate %q: unknown placeholder %s", template, placeholder)
+		}
+	}
+	// Rules may collect several kinds, across namespaces, so only a template with all three keeps distinct objects from
+	// sharing a file, where writing or deleting one would overwrite or remove the other.
+	for _, required := range []string{"{kind}", "{namespace}", "{name}"} {
+		if !strings.Contains(template, required) {
+			return fmt.Errorf("invalid pathTemplate %q: must contain {kind}, {namespace}, and {name} so that distinct objects do not share a file", template)
+		}
+	}
+	if path.IsAbs(template) || strings.HasPrefix(template, "\\") {
+		return fmt.Errorf("invalid pathTemplate %q: must be relative to the output directory", template)
+	}
+	for _, segment := range strings.Split(template, "/") {
+		if segment == ".." {
+			return fmt.Errorf("invalid pathTemplate %q: must not leave the output directory", template)
+		}
+	}
+	return nil
+}
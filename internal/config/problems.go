@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position identifies a location in a configuration file.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// String formats the position as file:line:column, or returns an empty string when the position is unknown.
+func (p Position) String() string {
+	if p.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Problem is a single validation failure, located at the configuration field that caused it.
+type Problem struct {
+	// Path is the field that caused the problem, such as "objects[2].labelSelector".
+	Path     string
+	Position Position
+	Err      error
+
+	// context is the prefix Validate adds when reporting the problem on its own.
+	context string
+}
+
+// Error formats the problem as position: path: message, leaving out the parts that are unknown.
+func (p Problem) Error() string {
+	var parts []string
+	if position := p.Position.String(); position != "" {
+		parts = append(parts, position)
+	}
+	if p.Path != "" {
+		parts = append(parts, p.Path)
+	}
+	parts = append(parts, p.Err.Error())
+	return strings.Join(parts, ": ")
+}
+
+func (p Problem) Unwrap() error {
+	return p.Err
+}
+
+// within returns the problem nested under the supplied path prefix and Validate context.
+func (p Problem) within(prefix, context string) Problem {
+	switch {
+	case p.Path == "":
+		p.Path = prefix
+	case strings.HasPrefix(p.Path, "["):
+		p.Path = prefix + p.Path
+	default:
+		p.Path = prefix + "." + p.Path
+	}
+	p.context = joinContext(context, p.context)
+	return p
+}
+
+func joinContext(outer, inner string) string {
+	switch {
+	case outer == "":
+		return inner
+	case inner == "":
+		return outer
+	default:
+		return outer + ": " + inner
+	}
+}
+
+func (p Problem) validationError() error {
+	if p.context == "" {
+		return p.Err
+	}
+	return fmt.Errorf("%s: %w", p.context, p.Err)
+}
+
+// locate returns the position of the field at path, falling back to its closest parent that has a known position.
+func (cfg *Config) locate(path string) Position {
+	for path != "" {
+		if position, ok := cfg.positions[path]; ok {
+			return position
+		}
+		cut := strings.LastIndexAny(path, ".[")
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	return Position{}
+}
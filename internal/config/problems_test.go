@@ -0,0 +1,71 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestProblemsReportsEveryProblemWithPosition(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	base := writeConfig(t, filepath.Join(dir, "base.yaml"), `
+refreshInterval: soon
+objects:
+  - apiVersion: v1
+    kind: Pod
+`)
+	team := writeConfig(t, filepath.Join(dir, "team.yaml"), `
+logging:
+  logDiffs: loud
+objects:
+  - apiVersion: apps/v1
+    kind: Deployment
+    labelSelector: "tier in ("
+    excludeNamePatterns: ["ok", "["]
+`)
+
+	cfg, err := Load(base, team)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	var reported []string
+	for _, problem := range cfg.Problems() {
+		reported = append(reported, problem.Position.String()+" "+problem.Path)
+	}
+	g.Expect(reported).To(gomega.Equal([]string{
+		team + ":3:3 logging.logDiffs",
+		team + ":8:33 objects[1].excludeNamePatterns[1]",
+		team + ":7:5 objects[1].labelSelector",
+		base + ":2:1 refreshInterval",
+	}))
+}
+
+func TestProblemError(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cfg := &Config{Objects: []ObjectRule{{APIVersion: "v1", Kind: "Pod", FieldSelector: "spec.nodeName"}}}
+	problems := cfg.Problems()
+	g.Expect(problems).To(gomega.HaveLen(1))
+	g.Expect(problems[0].Error()).To(gomega.HavePrefix(`objects[0].fieldSelector: invalid fieldSelector "spec.nodeName"`))
+	g.Expect(cfg.Validate().Error()).To(gomega.HavePrefix(`validate object rule 1: invalid fieldSelector "spec.nodeName"`))
+}
+
+func TestLoadReportsDecodeErrorsFromEveryFile(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	first := writeConfig(t, filepath.Join(dir, "first.yaml"), "refreshInterval: 1h\nunknown: true\n")
+	second := writeConfig(t, filepath.Join(dir, "second.yaml"), "objects:\n  - apiVersion: v1\n    knd: Pod\n")
+
+	_, err := Load(first, second)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("decode config " + first))
+	g.Expect(err.Error()).To(gomega.ContainSubstring("line 2: field unknown not found"))
+	g.Expect(err.Error()).To(gomega.ContainSubstring("decode config " + second))
+	g.Expect(err.Error()).To(gomega.ContainSubstring("line 3: field knd not found"))
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SchemaID is the identifier of the published configuration schema.
+const SchemaID = "https://raw.githubusercontent.com/grafana/k8s-manifest-tail/main/config.schema.json"
+
+// schemaProvider is implemented by configuration types whose YAML form is not derived from their Go type.
+type schemaProvider interface {
+	jsonSchema() map[string]any
+}
+
+// schemaDescriptions documents configuration fields, keyed by Go type and YAML field name.
+var schemaDescriptions = map[string]string{
+	"Config.output":                  "Controls where and how manifest files are written.",
+	"Config.logging":                 "Controls diff and manifest logging.",
+	"Config.refreshInterval":         "How often to fetch a full set of all objects: a Go duration such as 24h, a cron expression such as \"0 * * * *\", or \"never\".",
+	"Config.refreshJitter":           "Largest random delay, as a Go duration, added to each full refresh.",
+	"Config.namespaces":              "Namespaces, globs, or re: regular expressions to look for any objects. Empty means all namespaces.",
+	"Config.excludeNamespaces":       "Namespaces, globs, or re: regular expressions to skip for any objects.",
+	"Config.namespaceSelector":       "Label selector for the namespaces to look for any objects.",
+	"Config.filters":                 "Filters applied to every manifest before it is written, in order. Defaults to removeStatus, removeMetadataFields, redactEnvValues, and redactData; an empty list turns filtering off.",
+	"Config.objects":                 "Rules describing which kinds of objects to collect.",
+	"OutputConfig.directory":         "Directory, relative to the working directory, where manifest files are stored.",
+	"OutputConfig.format":            "Serialization format of manifest files.",
+	"OutputConfig.pathTemplate":      "Layout of manifest files within the directory, without the extension. Placeholders: {group}, {version}, {kind}, {namespace}, {name}; {kind}, {namespace}, and {name} are required.",
+	"LoggingConfig.logDiffs":         "Whether to log manifest diffs: false, compact, or detailed.",
+	"LoggingConfig.logManifests":     "Whether to log the manifests themselves.",
+	"LoggingConfig.otlp":             "OpenTelemetry log exporter settings.",
+	"OTLPConfig.endpoint":            "OTLP endpoint (host:port). Overrides the standard OTEL_EXPORTER_OTLP_* environment variables.",
+	"OTLPConfig.insecure":            "Whether to connect to the OTLP endpoint without TLS.",
+	"ObjectRule.apiVersion":          "API version of the objects, such as apps/v1. Use group/* with kind \"*\" to match every kind in a group.",
+	"ObjectRule.kind":                "Kind of the objects, or \"*\" for every kind in the API group or group version.",
+	"ObjectRule.namespaces":          "Namespaces, globs, or re: regular expressions to look for these objects. Overrides the global namespaces.",
+	"ObjectRule.namespaceSelector":   "Label selector for the namespaces to look for these objects. Overrides the global namespaceSelector.",
+	"ObjectRule.namePattern":         "Regular expression that object names must match.",
+	"ObjectRule.namePatterns":        "Regular expressions that object names must match at least one of.",
+	"ObjectRule.excludeNamePatterns": "Regular expressions for object names to skip.",
+	"ObjectRule.excludeKinds":        "Kinds to skip when kind is \"*\".",
+	"ObjectRule.labelSelector":       "Label selector the objects must match.",
+	"ObjectRule.fieldSelector":       "Field selector the objects must match, evaluated by the API server.",
+	"ObjectRule.refreshInterval":     "How often to fetch these objects again: a Go duration, a cron expression, or \"never\". Overrides the global refreshInterval.",
+	"ObjectRule.refreshJitter":       "Largest random delay added to each full refresh of these objects. Overrides the global refreshJitter.",
+	"ObjectRule.output":              "Output settings for these objects. Fields that are set override the global output settings.",
+	"ObjectRule.filters":             "Filters applied to these objects, in order. Replaces the global filters.",
+	"ObjectRule.patches":             "Patches applied to these objects after the filters, in order. An object that a patch does not apply to is skipped with a warning.",
+	"PatchConfig.kinds":              "Kinds of the objects the patch applies to. Empty means every kind the rule collects.",
+	"PatchConfig.json":               "RFC 6902 JSON patch, as a YAML or JSON list of operations, such as [{op: remove, path: /spec/replicas}].",
+	"PatchConfig.strategicMerge":     "Strategic merge patch, as a YAML or JSON partial object. Kinds that are not built into Kubernetes are merged as an RFC 7386 JSON merge patch.",
+	"FilterConfig.name":              "Name of the built-in filter.",
+	"FilterConfig.kinds":             "Kinds of the objects the filter applies to. Empty means every kind the rule collects.",
+	"FilterConfig.paths":             "removeFields: fields to remove, as JSONPath expressions such as spec.containers[*].terminationMessagePath or JSON Pointers such as /metadata/labels/app.",
+	"FilterConfig.fields":            "removeMetadataFields: metadata fields to remove. Defaults to managedFields, resourceVersion, uid, selfLink, generation, and creationTimestamp.",
+	"FilterConfig.replacement":       "redactEnvValues, redactData, and scrubSecrets: text that replaces each value. Defaults to <Redacted>.",
+	"FilterConfig.hash":              "redactEnvValues, redactData, and scrubSecrets: replace each value with a keyed fingerprint, such as <Redacted sha256:…>, so that changes remain visible.",
+	"FilterConfig.keyPatterns":       "redactData: regular expressions for the ConfigMap data and binaryData keys to redact. Secret values are always redacted.",
+	"FilterConfig.ruleSet":           "neat: version of the curated rules. Defaults to the latest; pin one to keep the output stable across upgrades.",
+	"FilterConfig.rules":             "neat: additional field paths to remove, keyed by kind or \"*\" for every kind.",
+	"FilterConfig.containerPaths":    "redactEnvValues: field paths of more containers, or lists of containers, keyed by kind or \"*\" for every kind. Pod specs are found anywhere in an object without them.",
+	"FilterConfig.detectors":         "scrubSecrets: built-in detectors to use. Defaults to every detector; an empty list turns them off.",
+	"FilterConfig.patterns":          "scrubSecrets: more regular expressions to redact in every string. With a capturing group, only the text of the first group is redacted.",
+	"FilterConfig.program":           "jq: program that transforms each object, such as del(.spec.replicas). A program that returns null or nothing drops the object.",
+	"FilterConfig.command":           "plugin: program to run, followed by its arguments. It reads one JSON request per line on stdin and writes one JSON response per line on stdout.",
+	"FilterConfig.timeout":           "plugin: how long to wait for each response; wasm: how long each module may run on an object. A Go duration; defaults to 10s.",
+	"FilterConfig.modules":           "wasm: paths of the WebAssembly modules to run, in order. Each exports memory, allocate, and filter.",
+	"FilterConfig.memoryLimit":       "wasm: memory each module may use, as a Kubernetes quantity such as 64Mi. Defaults to 64Mi.",
+	"FilterConfig.cacheDirectory":    "pruneDefaults: directory where the cluster's OpenAPI documents are cached. Defaults to k8s-manifest-tail/openapi in the user cache directory.",
+	"HashConfig.keyEnv":              "Environment variable holding the HMAC key.",
+	"HashConfig.keyFile":             "Path of a file holding the HMAC key. Trailing line breaks are ignored.",
+}
+
+// schemaRequired lists the fields that must be set, keyed by Go type.
+var schemaRequired = map[string][]string{
+	"ObjectRule":   {"apiVersion", "kind"},
+	"FilterConfig": {"name"},
+}
+
+// schemaEnums lists the allowed values of string fields, or of the items of string lists, keyed by Go type and YAML field name.
+var schemaEnums = map[string][]string{
+	"FilterConfig.name":      FilterNames(),
+	"FilterConfig.ruleSet":   NeatRuleSets,
+	"FilterConfig.detectors": SecretDetectors,
+}
+
+// JSONSchema returns a JSON Schema describing the configuration file, generated from the configuration types.
+func JSONSchema() ([]byte, error) {
+	defs := make(map[string]any)
+	root := objectSchema(reflect.TypeOf(Config{}), defs)
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = SchemaID
+	root["title"] = "k8s-manifest-tail configuration"
+	root["$defs"] = defs
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func typeSchema(t reflect.Type, defs map[string]any) map[string]any {
+	if provider, ok := reflect.Zero(t).Interface().(schemaProvider); ok {
+		return provider.jsonSchema()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), defs)}
+	case reflect.Pointer:
+		return typeSchema(t.Elem(), defs)
+	case reflect.Struct:
+		if _, ok := defs[t.Name()]; !ok {
+			defs[t.Name()] = nil // Reserve the name so recursive types terminate.
+			defs[t.Name()] = objectSchema(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+// objectSchema describes a struct through its yaml-tagged fields. Unknown fields are rejected, matching the strict
+// decoding used by Load.
+func objectSchema(t reflect.Type, defs map[string]any) map[string]any {
+	properties := make(map[string]any)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		property := typeSchema(field.Type, defs)
+		if description, ok := schemaDescriptions[t.Name()+"."+name]; ok {
+			property["description"] = description
+		}
+		if values, ok := schemaEnums[t.Name()+"."+name]; ok {
+			if items, ok := property["items"].(map[string]any); ok {
+				items["enum"] = values
+			} else {
+				property["enum"] = values
+			}
+		}
+		properties[name] = property
+	}
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if required, ok := schemaRequired[t.Name()]; ok {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (OutputFormat) jsonSchema() map[string]any {
+	return map[string]any{"type": "string", "enum": []string{string(OutputFormatYAML), string(OutputFormatJSON)}}
+}
+
+func (LogDiffMode) jsonSchema() map[string]any {
+	return map[string]any{
+		"oneOf": []any{
+			map[string]any{"type": "boolean"},
+			map[string]any{"type": "string", "enum": []string{
+				"false", string(LogDiffsDisabled), string(LogDiffsCompact), string(LogDiffsDetailed),
+			}},
+		},
+	}
+}
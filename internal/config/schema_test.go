@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestJSONSchemaIsPublished(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	schema, err := JSONSchema()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	published, err := os.ReadFile(filepath.Join("..", "..", "config.schema.json"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(published)).To(gomega.Equal(string(schema)), "config.schema.json is out of date, run: make config.schema.json")
+}
+
+func TestJSONSchemaDescribesConfiguration(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	data, err := JSONSchema()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+		Defs       map[string]struct {
+			Properties           map[string]map[string]any `json:"properties"`
+			Required             []string                  `json:"required"`
+			AdditionalProperties bool                      `json:"additionalProperties"`
+		} `json:"$defs"`
+	}
+	g.Expect(json.Unmarshal(data, &schema)).To(gomega.Succeed())
+
+	g.Expect(schema.Properties).To(gomega.HaveKey("objects"))
+	g.Expect(schema.Properties["output"]["$ref"]).To(gomega.Equal("#/$defs/OutputConfig"))
+
+	rule := schema.Defs["ObjectRule"]
+	g.Expect(rule.Required).To(gomega.Equal([]string{"apiVersion", "kind"}))
+	g.Expect(rule.AdditionalProperties).To(gomega.BeFalse())
+	g.Expect(rule.Properties).To(gomega.HaveKey("labelSelector"))
+	g.Expect(rule.Properties).NotTo(gomega.HaveKey("names"))
+
+	format := schema.Defs["OutputConfig"].Properties["format"]
+	g.Expect(format["enum"]).To(gomega.ConsistOf("yaml", "json"))
+
+	for name, def := range schema.Defs {
+		for property, value := range def.Properties {
+			g.Expect(value).To(gomega.HaveKey("description"), "%s.%s has no description", name, property)
+		}
+	}
+}
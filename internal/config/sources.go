@@ -0,0 +1,240 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ResolvePaths expands the supplied configuration paths into the files to load, in order. A directory contributes its
+// *.yaml and *.yml files in lexical order; hidden entries, such as the ..data links of a ConfigMap volume, are skipped.
+func ResolvePaths(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		if strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("config path is required")
+		}
+		info, err := statConfigPath(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config directory: %w", err)
+		}
+		found := false
+		for _, entry := range entries {
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") {
+				continue
+			}
+			if ext := filepath.Ext(name); ext != ".yaml" && ext != ".yml" {
+				continue
+			}
+			file := filepath.Join(path, name)
+			if info, err := os.Stat(file); err != nil || info.IsDir() {
+				continue
+			}
+			files = append(files, file)
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("no configuration files found in directory %s", path)
+		}
+	}
+	return files, nil
+}
+
+func statConfigPath(path string) (os.FileInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open config: %w", err)
+	}
+	defer func(file *os.File) { _ = file.Close() }(file)
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("open config: %w", err)
+	}
+	return info, nil
+}
+
+// configSource is the parsed document of one configuration file, along with where each of its object rules starts and
+// where each of its fields is. Object rules are indexed from ruleOffset, their position in the merged configuration.
+type configSource struct {
+	root      *yaml.Node
+	origins   []string
+	positions map[string]Position
+}
+
+func readSource(path string, ruleOffset int) (*configSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("open config: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode config %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return &configSource{}, nil
+	}
+	if err := interpolateEnv(&doc); err != nil {
+		return nil, fmt.Errorf("interpolate config %s: %w", path, err)
+	}
+
+	// Decode strictly first so unknown fields and invalid values are reported against the file they appear in.
+	var cfg Config
+	if err := decodeStrict(&doc, &cfg); err != nil {
+		return nil, fmt.Errorf("decode config %s: %w", path, err)
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return &configSource{}, nil
+	}
+	source := &configSource{root: doc.Content[0], positions: make(map[string]Position)}
+	recordPositions(source.positions, path, "", source.root, ruleOffset)
+	if objects := mappingValue(source.root, "objects"); objects != nil && objects.Kind == yaml.SequenceNode {
+		for _, item := range objects.Content {
+			source.origins = append(source.origins, fmt.Sprintf("%s:%d", path, item.Line))
+		}
+	}
+	return source, nil
+}
+
+// decodeStrict decodes a document into out, failing on fields that out does not have. Only a yaml.Decoder checks for
+// unknown fields, so the document is encoded again first; the lines that errors name are those of the document.
+func decodeStrict(doc *yaml.Node, out interface{}) error {
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(out)
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+	var encoded yaml.Node
+	if yaml.Unmarshal(data, &encoded) != nil {
+		return err
+	}
+	lines := make(map[int]int)
+	matchLines(lines, &encoded, doc)
+	for i, message := range typeErr.Errors {
+		typeErr.Errors[i] = errorLinePattern.ReplaceAllStringFunc(message, func(match string) string {
+			line, _ := strconv.Atoi(errorLinePattern.FindStringSubmatch(match)[1])
+			if original, ok := lines[line]; ok {
+				line = original
+			}
+			return fmt.Sprintf("line %d:", line)
+		})
+	}
+	return typeErr
+}
+
+// errorLinePattern matches the line that a yaml.TypeError message starts with.
+var errorLinePattern = regexp.MustCompile(`^line (\d+):`)
+
+// matchLines maps the lines of an encoded copy of a document to the lines of the document.
+func matchLines(lines map[int]int, encoded, original *yaml.Node) {
+	if _, ok := lines[encoded.Line]; !ok {
+		lines[encoded.Line] = original.Line
+	}
+	if len(encoded.Content) != len(original.Content) {
+		return
+	}
+	for i := range encoded.Content {
+		matchLines(lines, encoded.Content[i], original.Content[i])
+	}
+}
+
+// recordPositions records the position of every mapping key and sequence item under node, keyed by field path such as
+// "objects[2].labelSelector".
+func recordPositions(positions map[string]Position, file, path string, node *yaml.Node, ruleOffset int) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			child := key.Value
+			if path != "" {
+				child = path + "." + key.Value
+			}
+			positions[child] = Position{File: file, Line: key.Line, Column: key.Column}
+			recordPositions(positions, file, child, value, ruleOffset)
+		}
+	case yaml.SequenceNode:
+		offset := 0
+		if path == "objects" {
+			offset = ruleOffset
+		}
+		for i, item := range node.Content {
+			child := fmt.Sprintf("%s[%d]", path, i+offset)
+			positions[child] = Position{File: file, Line: item.Line, Column: item.Column}
+			recordPositions(positions, file, child, item, ruleOffset)
+		}
+	}
+}
+
+// mergeSources combines the documents in order. Later values replace earlier ones, nested mappings are merged key by
+// key, and the top-level objects lists are appended.
+func mergeSources(sources []*configSource) (*yaml.Node, []string, map[string]Position) {
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	var origins []string
+	positions := make(map[string]Position)
+	for _, source := range sources {
+		if source.root == nil {
+			continue
+		}
+		mergeMapping(merged, source.root, true)
+		origins = append(origins, source.origins...)
+		for path, position := range source.positions {
+			positions[path] = position
+		}
+	}
+	return merged, origins, positions
+}
+
+func mergeMapping(dst, src *yaml.Node, topLevel bool) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		existing := mappingValue(dst, key.Value)
+		switch {
+		case existing == nil:
+			dst.Content = append(dst.Content, key, value)
+		case topLevel && key.Value == "objects":
+			if value.Kind != yaml.SequenceNode {
+				continue
+			}
+			if existing.Kind == yaml.SequenceNode {
+				existing.Content = append(existing.Content, value.Content...)
+			} else {
+				*existing = *value
+			}
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			mergeMapping(existing, value, false)
+		default:
+			*existing = *value
+		}
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func writeConfig(t *testing.T, path, contents string) string {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadMergesFilesInOrder(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	base := writeConfig(t, filepath.Join(dir, "base.yaml"), `
+output:
+  directory: base
+  format: json
+refreshInterval: 1h
+namespaces: ["default"]
+objects:
+  - apiVersion: v1
+    kind: Pod
+`)
+	team := writeConfig(t, filepath.Join(dir, "team.yaml"), `
+output:
+  directory: team
+namespaces: ["payments"]
+objects:
+  - apiVersion: apps/v1
+    kind: Deployment
+`)
+
+	cfg, err := Load(base, team)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cfg.Output.Directory).To(gomega.Equal("team"))
+	g.Expect(cfg.Output.Format).To(gomega.Equal(OutputFormatJSON))
+	g.Expect(cfg.RefreshInterval).To(gomega.Equal("1h"))
+	g.Expect(cfg.Namespaces).To(gomega.Equal([]string{"payments"}))
+	g.Expect(cfg.Objects).To(gomega.HaveLen(2))
+	g.Expect(cfg.Objects[0].Kind).To(gomega.Equal("Pod"))
+	g.Expect(cfg.Objects[0].Origin()).To(gomega.Equal(base + ":8"))
+	g.Expect(cfg.Objects[1].Kind).To(gomega.Equal("Deployment"))
+	g.Expect(cfg.Objects[1].Origin()).To(gomega.Equal(team + ":6"))
+}
+
+func TestLoadDirectory(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	writeConfig(t, filepath.Join(dir, "20-team.yml"), `
+objects:
+  - apiVersion: v1
+    kind: Service
+`)
+	writeConfig(t, filepath.Join(dir, "10-base.yaml"), `
+refreshInterval: 2h
+objects:
+  - apiVersion: v1
+    kind: Pod
+`)
+	writeConfig(t, filepath.Join(dir, "README.md"), "not configuration")
+	writeConfig(t, filepath.Join(dir, ".hidden.yaml"), "unknown: true")
+	g.Expect(os.Mkdir(filepath.Join(dir, "..data"), 0o700)).To(gomega.Succeed())
+
+	cfg, err := Load(dir)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cfg.RefreshInterval).To(gomega.Equal("2h"))
+	g.Expect(cfg.Objects).To(gomega.HaveLen(2))
+	g.Expect(cfg.Objects[0].Kind).To(gomega.Equal("Pod"))
+	g.Expect(cfg.Objects[1].Kind).To(gomega.Equal("Service"))
+}
+
+func TestLoadEmptyDirectory(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	_, err := Load(dir)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal("no configuration files found in directory " + dir))
+}
+
+func TestLoadReportsUnknownFieldsWithFile(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	base := writeConfig(t, filepath.Join(dir, "base.yaml"), "refreshInterval: 1h\n")
+	broken := writeConfig(t, filepath.Join(dir, "broken.yaml"), "objects:\n  - apiVersion: v1\n    knd: Pod\n")
+
+	_, err := Load(base, broken)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("decode config " + broken))
+	g.Expect(err.Error()).To(gomega.ContainSubstring("line 3"))
+}
+
+func TestLoadReportsUnknownFieldsAtTheirLine(t *testing.T) {
+	g := gomega.NewWithT(t)
+	t.Setenv("MANIFEST_TAIL_TEST_KIND", "Pod")
+
+	path := writeConfig(t, filepath.Join(t.TempDir(), "config.yaml"), `# Collected by the platform team.
+
+refreshInterval: 1h
+
+
+objects:
+  - apiVersion: v1
+
+    kind: ${MANIFEST_TAIL_TEST_KIND}
+    namespace: default
+`)
+
+	_, err := Load(path)
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("line 10: field namespace not found in type config.ObjectRule")))
+}
+
+func TestValidateReportsDuplicateRulesWithOrigins(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	first := writeConfig(t, filepath.Join(dir, "first.yaml"), `
+objects:
+  - apiVersion: v1
+    kind: Pod
+    namespaces: ["default"]
+`)
+	second := writeConfig(t, filepath.Join(dir, "second.yaml"), `
+objects:
+  - apiVersion: v1
+    kind: Pod
+    namespaces: ["prod"]
+  - apiVersion: v1
+    kind: Pod
+    namespaces: ["default"]
+`)
+
+	cfg, err := Load(first, second)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	err = cfg.Validate()
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal(
+		"duplicate object rule for v1 Pod: defined at object rule 1 (" + first + ":3) and object rule 3 (" + second + ":6)",
+	))
+}
+
+func TestValidateReportsRuleOrigin(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	path := writeConfig(t, filepath.Join(t.TempDir(), "config.yaml"), `
+objects:
+  - apiVersion: v1
+    kind: Pod
+    labelSelector: "app in ("
+`)
+
+	cfg, err := Load(path)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	err = cfg.Validate()
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.HavePrefix("validate object rule 1 (" + path + ":3): invalid labelSelector"))
+}
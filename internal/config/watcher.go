@@ -0,0 +1,72 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Watcher polls configuration files and reports when their contents change. Polling the file contents, rather than
+// relying on filesystem notifications, also follows ConfigMap volume updates, which swap a symlink instead of writing
+// to the file.
+type Watcher struct {
+	paths    []string
+	interval time.Duration
+	last     [sha256.Size]byte
+}
+
+// NewWatcher builds a Watcher for the supplied files and directories, recording their current contents as the
+// baseline. Directories are re-read on every poll, so files added to or removed from them also count as a change.
+func NewWatcher(interval time.Duration, paths ...string) (*Watcher, error) {
+	w := &Watcher{
+		paths:    paths,
+		interval: interval,
+	}
+	sum, err := w.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	w.last = sum
+	return w, nil
+}
+
+// Run polls until the context is cancelled, calling onChange each time the watched contents change. Files that cannot
+// be read are skipped until the next poll, which covers the brief window where a ConfigMap update is in progress.
+func (w *Watcher) Run(ctx context.Context, onChange func()) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sum, err := w.fingerprint()
+			if err != nil || sum == w.last {
+				continue
+			}
+			w.last = sum
+			onChange()
+		}
+	}
+}
+
+func (w *Watcher) fingerprint() ([sha256.Size]byte, error) {
+	files, err := ResolvePaths(w.paths)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	hash := sha256.New()
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return [sha256.Size]byte{}, fmt.Errorf("read config %s: %w", path, err)
+		}
+		_, _ = fmt.Fprintf(hash, "%s\x00%d\x00", path, len(data))
+		hash.Write(data)
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], hash.Sum(nil))
+	return sum, nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestWatcherReportsChangedContents(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	g.Expect(os.WriteFile(path, []byte("refreshInterval: 1h\n"), 0o600)).To(gomega.Succeed())
+
+	watcher, err := NewWatcher(10*time.Millisecond, path)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	changes := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx, func() { changes <- struct{}{} })
+
+	g.Consistently(changes, 50*time.Millisecond).ShouldNot(gomega.Receive())
+	g.Expect(os.WriteFile(path, []byte("refreshInterval: 2h\n"), 0o600)).To(gomega.Succeed())
+	g.Eventually(changes).Should(gomega.Receive())
+	g.Consistently(changes, 50*time.Millisecond).ShouldNot(gomega.Receive())
+}
+
+func TestWatcherFollowsConfigMapSymlinkSwap(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	// Mimic the layout of a ConfigMap volume: config.yaml -> ..data/config.yaml, ..data -> timestamped directory.
+	dir := t.TempDir()
+	for name, contents := range map[string]string{"v1": "refreshInterval: 1h\n", "v2": "refreshInterval: 2h\n"} {
+		g.Expect(os.Mkdir(filepath.Join(dir, name), 0o700)).To(gomega.Succeed())
+		g.Expect(os.WriteFile(filepath.Join(dir, name, "config.yaml"), []byte(contents), 0o600)).To(gomega.Succeed())
+	}
+	g.Expect(os.Symlink("v1", filepath.Join(dir, "..data"))).To(gomega.Succeed())
+	path := filepath.Join(dir, "config.yaml")
+	g.Expect(os.Symlink(filepath.Join("..data", "config.yaml"), path)).To(gomega.Succeed())
+
+	watcher, err := NewWatcher(10*time.Millisecond, path)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	changes := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx, func() { changes <- struct{}{} })
+
+	g.Expect(os.Symlink("v2", filepath.Join(dir, "..data_tmp"))).To(gomega.Succeed())
+	g.Expect(os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data"))).To(gomega.Succeed())
+	g.Eventually(changes).Should(gomega.Receive())
+}
+
+func TestNewWatcherMissingFile(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	_, err := NewWatcher(time.Second, filepath.Join(t.TempDir(), "missing.yaml"))
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("open config"))
+}
+
+func TestWatcherReportsFilesAddedToDirectory(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "base.yaml"), []byte("refreshInterval: 1h\n"), 0o600)).To(gomega.Succeed())
+
+	watcher, err := NewWatcher(10*time.Millisecond, dir)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	changes := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx, func() { changes <- struct{}{} })
+
+	g.Expect(os.WriteFile(filepath.Join(dir, "team.yaml"), []byte("objects: []\n"), 0o600)).To(gomega.Succeed())
+	g.Eventually(changes).Should(gomega.Receive())
+}
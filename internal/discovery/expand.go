@@ -0,0 +1,157 @@
+package discovery
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sdiscovery "k8s.io/client-go/discovery"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+// ExpandConfig returns a configuration whose wildcard rules have been replaced by concrete rules discovered from the
+// API server. The supplied configuration is returned unchanged when it has no wildcard rules.
+func ExpandConfig(client k8sdiscovery.DiscoveryInterface, cfg *config.Config) (*config.Config, error) {
+	if !cfg.HasWildcardRules() {
+		return cfg, nil
+	}
+	rules, err := ExpandRules(client, cfg.Objects)
+	if err != nil {
+		return nil, err
+	}
+	expanded := *cfg
+	expanded.Objects = rules
+	return &expanded, nil
+}
+
+// ExpandRules replaces each wildcard rule with one rule per listable and watchable resource in its API group or group
+// version. Kinds for which an explicit rule already collects every object the wildcard rule would are skipped, so they
+// are not collected twice. An explicit rule that is narrower, for example limited to fewer namespaces, leaves the
+// wildcard rule in place for the rest.
+func ExpandRules(client k8sdiscovery.DiscoveryInterface, rules []config.ObjectRule) ([]config.ObjectRule, error) {
+	explicit := make(map[schema.GroupKind][]config.ObjectRule)
+	for _, rule := range rules {
+		if rule.IsWildcard() {
+			continue
+		}
+		if gv, err := schema.ParseGroupVersion(rule.APIVersion); err == nil {
+			groupKind := schema.GroupKind{Group: gv.Group, Kind: rule.Kind}
+			explicit[groupKind] = append(explicit[groupKind], rule)
+		}
+	}
+
+	var expanded []config.ObjectRule
+	for _, rule := range rules {
+		if !rule.IsWildcard() {
+			expanded = append(expanded, rule)
+			continue
+		}
+		if client == nil {
+			return nil, fmt.Errorf("expand %s rule: discovery client is not available", rule.APIVersion)
+		}
+		resources, err := discoverResources(client, rule.APIVersion)
+		if err != nil {
+			return nil, fmt.Errorf("expand %s rule: %w", rule.APIVersion, err)
+		}
+		for _, resource := range resources {
+			if slices.ContainsFunc(explicit[schema.GroupKind{Group: resource.group.Group, Kind: resource.Kind}], func(covering config.ObjectRule) bool {
+				return covers(covering, rule)
+			}) {
+				continue
+			}
+			if slices.Contains(rule.ExcludeKinds, resource.Kind) {
+				continue
+			}
+			concrete := rule
+			concrete.APIVersion = resource.group.String()
+			concrete.Kind = resource.Kind
+			concrete.ExcludeKinds = nil
+			expanded = append(expanded, concrete)
+		}
+	}
+	return expanded, nil
+}
+
+// covers reports whether an explicit rule collects every object of its kind that a wildcard rule would: it is limited by
+// no namespace, selector, or name pattern that the wildcard rule does not share.
+func covers(explicit, wildcard config.ObjectRule) bool {
+	if len(explicit.Namespaces) > 0 {
+		if len(wildcard.Namespaces) == 0 || slices.ContainsFunc(wildcard.Namespaces, func(namespace string) bool {
+			return !slices.Contains(explicit.Namespaces, namespace)
+		}) {
+			return false
+		}
+	}
+	for _, selectors := range [][2]string{
+		{explicit.NamespaceSelector, wildcard.NamespaceSelector},
+		{explicit.LabelSelector, wildcard.LabelSelector},
+		{explicit.FieldSelector, wildcard.FieldSelector},
+	} {
+		if strings.TrimSpace(selectors[0]) != "" && strings.TrimSpace(selectors[0]) != strings.TrimSpace(selectors[1]) {
+			return false
+		}
+	}
+	if len(explicit.IncludeNamePatterns()) == 0 && len(explicit.ExcludeNamePatterns) == 0 {
+		return true
+	}
+	return slices.Equal(explicit.IncludeNamePatterns(), wildcard.IncludeNamePatterns()) &&
+		slices.Equal(explicit.ExcludeNamePatterns, wildcard.ExcludeNamePatterns)
+}
+
+type discoveredResource struct {
+	metav1.APIResource
+	group schema.GroupVersion
+}
+
+// discoverResources returns the listable and watchable resources for a group version, or for the preferred version of
+// a group when the version is the wildcard.
+func discoverResources(client k8sdiscovery.DiscoveryInterface, apiVersion string) ([]discoveredResource, error) {
+	groupVersion := apiVersion
+	if group, ok := strings.CutSuffix(apiVersion, "/"+config.WildcardVersion); ok {
+		preferred, err := preferredGroupVersion(client, group)
+		if err != nil {
+			return nil, err
+		}
+		groupVersion = preferred
+	}
+
+	gv, err := schema.ParseGroupVersion(groupVersion)
+	if err != nil {
+		return nil, fmt.Errorf("parse apiVersion %q: %w", groupVersion, err)
+	}
+	list, err := client.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return nil, fmt.Errorf("discover resources for %s: %w", groupVersion, err)
+	}
+
+	var resources []discoveredResource
+	for _, resource := range list.APIResources {
+		if strings.Contains(resource.Name, "/") {
+			continue
+		}
+		if !slices.Contains(resource.Verbs, "list") || !slices.Contains(resource.Verbs, "watch") {
+			continue
+		}
+		resources = append(resources, discoveredResource{APIResource: resource, group: gv})
+	}
+	slices.SortFunc(resources, func(a, b discoveredResource) int {
+		return strings.Compare(a.Kind, b.Kind)
+	})
+	return resources, nil
+}
+
+func preferredGroupVersion(client k8sdiscovery.DiscoveryInterface, group string) (string, error) {
+	groups, err := client.ServerGroups()
+	if err != nil {
+		return "", fmt.Errorf("discover API groups: %w", err)
+	}
+	for _, apiGroup := range groups.Groups {
+		if apiGroup.Name == group {
+			return apiGroup.PreferredVersion.GroupVersion, nil
+		}
+	}
+	return "", fmt.Errorf("API group %q is not served by the cluster", group)
+}
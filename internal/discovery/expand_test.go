@@ -0,0 +1,105 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+func newFakeDiscovery() *discoveryfake.FakeDiscovery {
+	listWatch := metav1.Verbs{"get", "list", "watch"}
+	return &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{
+			GroupVersion: "rbac.authorization.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "roles", Kind: "Role", Namespaced: true, Verbs: listWatch},
+				{Name: "rolebindings", Kind: "RoleBinding", Namespaced: true, Verbs: listWatch},
+				{Name: "clusterroles", Kind: "ClusterRole", Verbs: listWatch},
+				{Name: "clusterrolebindings", Kind: "ClusterRoleBinding", Verbs: listWatch},
+			},
+		},
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: listWatch},
+				{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: metav1.Verbs{"get"}},
+				{Name: "bindings", Kind: "Binding", Namespaced: true, Verbs: metav1.Verbs{"create"}},
+				{Name: "secrets", Kind: "Secret", Namespaced: true, Verbs: listWatch},
+				{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: listWatch},
+			},
+		},
+	}}}
+}
+
+func TestExpandRulesForGroupWildcard(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	rules, err := ExpandRules(newFakeDiscovery(), []config.ObjectRule{
+		{APIVersion: "rbac.authorization.k8s.io/*", Kind: "*", ExcludeKinds: []string{"ClusterRoleBinding"}, LabelSelector: "team=a"},
+	})
+
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(rules).To(gomega.Equal([]config.ObjectRule{
+		{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", LabelSelector: "team=a"},
+		{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role", LabelSelector: "team=a"},
+		{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding", LabelSelector: "team=a"},
+	}))
+}
+
+func TestExpandRulesSkipsUnwatchableAndExplicitKinds(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	rules, err := ExpandRules(newFakeDiscovery(), []config.ObjectRule{
+		{APIVersion: "v1", Kind: "Pod"},
+		{APIVersion: "v1", Kind: "*", Namespaces: []string{"default"}, ExcludeKinds: []string{"Secret"}},
+	})
+
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(rules).To(gomega.Equal([]config.ObjectRule{
+		{APIVersion: "v1", Kind: "Pod"},
+		{APIVersion: "v1", Kind: "ConfigMap", Namespaces: []string{"default"}},
+	}))
+}
+
+func TestExpandRulesKeepsKindsOfNarrowerExplicitRules(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	for _, explicit := range []config.ObjectRule{
+		{APIVersion: "v1", Kind: "Pod", Namespaces: []string{"default"}},
+		{APIVersion: "v1", Kind: "Pod", LabelSelector: "app=api"},
+		{APIVersion: "v1", Kind: "Pod", NamePattern: "api-*"},
+	} {
+		rules, err := ExpandRules(newFakeDiscovery(), []config.ObjectRule{explicit, {APIVersion: "v1", Kind: "*", ExcludeKinds: []string{"Secret"}}})
+
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(rules).To(gomega.Equal([]config.ObjectRule{
+			explicit,
+			{APIVersion: "v1", Kind: "ConfigMap"},
+			{APIVersion: "v1", Kind: "Pod"},
+		}))
+	}
+}
+
+func TestExpandRulesUnknownGroup(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	_, err := ExpandRules(newFakeDiscovery(), []config.ObjectRule{{APIVersion: "argoproj.io/*", Kind: "*"}})
+
+	g.Expect(err).To(gomega.MatchError(`expand argoproj.io/* rule: API group "argoproj.io" is not served by the cluster`))
+}
+
+func TestExpandConfigLeavesConcreteRulesAlone(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	cfg := &config.Config{Objects: []config.ObjectRule{{APIVersion: "v1", Kind: "Pod"}}}
+	expanded, err := ExpandConfig(nil, cfg)
+
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(expanded).To(gomega.BeIdenticalTo(cfg))
+}
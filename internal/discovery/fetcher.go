@@ -3,8 +3,6 @@ package discovery
 import (
 	"context"
 	"fmt"
-	"regexp"
-	"strings"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -47,15 +45,11 @@ func (f *Fetcher) FetchResources(ctx context.Context, rule config.ObjectRule) ([
 
 // fetchClusterScoped returns all Cluster-scoped objects that match a single rule.
 func (f *Fetcher) fetchClusterScoped(ctx context.Context, client dynamic.NamespaceableResourceInterface, rule config.ObjectRule) ([]unstructured.Unstructured, error) {
-	list, err := client.Namespace("").List(ctx, metav1.ListOptions{})
+	list, err := client.Namespace("").List(ctx, ListOptions(rule))
 	if err != nil {
 		return nil, fmt.Errorf("list %s (cluster-scoped): %w", rule.Kind, err)
 	}
-	filtered, err := filterByNamePattern(list.Items, rule.NamePattern)
-	if err != nil {
-		return nil, fmt.Errorf("filter %s by name: %w", rule.Kind, err)
-	}
-	return filtered, nil
+	return filterByName(list.Items, rule), nil
 }
 
 // fetchNamespaced returns all namespaced objects that match a single rule.
@@ -65,24 +59,38 @@ func (f *Fetcher) fetchNamespaced(ctx context.Context, client dynamic.Namespacea
 		namespaces = rule.Namespaces
 	}
 
+	matcher, err := NewNamespaceMatcher(rule, f.cfg)
+	if err != nil {
+		return nil, err
+	}
+	if matcher != nil {
+		namespaces, _, err = matcher.List(ctx, f.clients.Dynamic)
+		if err != nil {
+			return nil, err
+		}
+		if len(namespaces) == 0 {
+			return nil, nil
+		}
+	}
+
 	if len(namespaces) == 0 {
-		list, err := client.Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		list, err := client.Namespace(metav1.NamespaceAll).List(ctx, ListOptions(rule))
 		if err != nil {
 			return nil, fmt.Errorf("list %s across namespaces: %w", rule.Kind, err)
 		}
 		filtered := filterExcluded(list.Items, f.cfg.ExcludeNamespaces)
-		return applyNameFilter(filtered, rule, rule.Kind)
+		return filterByName(filtered, rule), nil
 	}
 
 	var all []unstructured.Unstructured
 	for _, ns := range namespaces {
-		list, err := client.Namespace(ns).List(ctx, metav1.ListOptions{})
+		list, err := client.Namespace(ns).List(ctx, ListOptions(rule))
 		if err != nil {
 			return nil, fmt.Errorf("list %s in namespace %s: %w", rule.Kind, ns, err)
 		}
 		all = append(all, list.Items...)
 	}
-	return applyNameFilter(all, rule, rule.Kind)
+	return filterByName(all, rule), nil
 }
 
 func MappingFromRule(mapper meta.RESTMapper, rule config.ObjectRule) (*meta.RESTMapping, error) {
@@ -109,42 +117,22 @@ func filterExcluded(items []unstructured.Unstructured, excludedNamespaces []stri
 	}
 	var filtered []unstructured.Unstructured
 	for _, item := range items {
-		isExcluded := false
-		for _, excludedNamespace := range excludedNamespaces {
-			if item.GetNamespace() == excludedNamespace {
-				isExcluded = true
-				break
-			}
-		}
-		if !isExcluded {
+		if !ShouldExcludeNamespace(item.GetNamespace(), excludedNamespaces) {
 			filtered = append(filtered, item)
 		}
 	}
 	return filtered
 }
 
-func applyNameFilter(items []unstructured.Unstructured, rule config.ObjectRule, kind string) ([]unstructured.Unstructured, error) {
-	filtered, err := filterByNamePattern(items, rule.NamePattern)
-	if err != nil {
-		return nil, fmt.Errorf("filter %s by name: %w", kind, err)
-	}
-	return filtered, nil
-}
-
-func filterByNamePattern(items []unstructured.Unstructured, pattern string) ([]unstructured.Unstructured, error) {
-	pattern = strings.TrimSpace(pattern)
-	if pattern == "" {
-		return items, nil
-	}
-	re, err := regexp.Compile(pattern)
-	if err != nil {
-		return nil, fmt.Errorf("compile name pattern: %w", err)
+func filterByName(items []unstructured.Unstructured, rule config.ObjectRule) []unstructured.Unstructured {
+	if len(rule.IncludeNamePatterns()) == 0 && len(rule.ExcludeNamePatterns) == 0 {
+		return items
 	}
 	var filtered []unstructured.Unstructured
 	for _, item := range items {
-		if re.MatchString(item.GetName()) {
+		if rule.MatchesName(item.GetName()) {
 			filtered = append(filtered, item)
 		}
 	}
-	return filtered, nil
+	return filtered
 }
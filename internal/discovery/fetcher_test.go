@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
 
 	"github.com/grafana/k8s-manifest-tail/internal/config"
 	"github.com/grafana/k8s-manifest-tail/internal/kube"
@@ -188,6 +189,233 @@ func TestFetcherAppliesNamePattern(t *testing.T) {
 	g.Expect(objectNames(items)).To(gomega.Equal([]string{"alloy-logs", "alloy-metrics"}))
 }
 
+func TestFetcherAppliesLabelSelector(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+	cfg := &config.Config{Namespaces: []string{"default"}}
+	alloy := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "alloy",
+		Namespace: "default",
+		Labels:    map[string]string{"app.kubernetes.io/part-of": "observability"},
+	}}
+	web := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "web",
+		Namespace: "default",
+		Labels:    map[string]string{"app.kubernetes.io/part-of": "storefront"},
+	}}
+	clients := newTestClients(
+		[]runtime.Object{alloy, web},
+		[]resourceMapping{
+			{
+				GVR:   corev1.SchemeGroupVersion.WithResource("pods"),
+				GVK:   corev1.SchemeGroupVersion.WithKind("Pod"),
+				Scope: meta.RESTScopeNamespace,
+			},
+		},
+	)
+
+	fetcher := NewFetcher(clients, cfg)
+	items, err := fetcher.FetchResources(ctx, config.ObjectRule{
+		APIVersion:    "v1",
+		Kind:          "Pod",
+		LabelSelector: "app.kubernetes.io/part-of in (observability)",
+	})
+
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(objectNames(items)).To(gomega.Equal([]string{"alloy"}))
+}
+
+func TestFetcherPassesFieldSelector(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+	cfg := &config.Config{Namespaces: []string{"default", "prod"}}
+	clients := newTestClients(
+		nil,
+		[]resourceMapping{
+			{
+				GVR:   corev1.SchemeGroupVersion.WithResource("pods"),
+				GVK:   corev1.SchemeGroupVersion.WithKind("Pod"),
+				Scope: meta.RESTScopeNamespace,
+			},
+		},
+	)
+	var selectors []string
+	clients.Dynamic.(*dynamicfake.FakeDynamicClient).PrependReactor("list", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		selectors = append(selectors, action.(clienttesting.ListAction).GetListRestrictions().Fields.String())
+		return false, nil, nil
+	})
+
+	fetcher := NewFetcher(clients, cfg)
+	_, err := fetcher.FetchResources(ctx, config.ObjectRule{
+		APIVersion:    "v1",
+		Kind:          "Pod",
+		FieldSelector: "spec.nodeName=node-a",
+	})
+
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(selectors).To(gomega.Equal([]string{"spec.nodeName=node-a", "spec.nodeName=node-a"}))
+}
+
+func TestFetcherAppliesNamespaceSelector(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+	cfg := &config.Config{
+		NamespaceSelector: "tenant=true",
+		ExcludeNamespaces: []string{"tenant-c"},
+	}
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: map[string]string{"tenant": "true"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-c", Labels: map[string]string{"tenant": "true"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "tenant-a"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "tenant-c"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+	}
+	clients := newTestClients(
+		objects,
+		[]resourceMapping{
+			{
+				GVR:   corev1.SchemeGroupVersion.WithResource("pods"),
+				GVK:   corev1.SchemeGroupVersion.WithKind("Pod"),
+				Scope: meta.RESTScopeNamespace,
+			},
+		},
+	)
+
+	fetcher := NewFetcher(clients, cfg)
+	items, err := fetcher.FetchResources(ctx, config.ObjectRule{APIVersion: "v1", Kind: "Pod"})
+
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(items).To(gomega.HaveLen(1))
+	g.Expect(items[0].GetNamespace()).To(gomega.Equal("tenant-a"))
+}
+
+func TestFetcherRuleNamespaceSelectorNarrowsNamespaces(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+	cfg := &config.Config{}
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: map[string]string{"tier": "gold"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-b", Labels: map[string]string{"tier": "gold"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "tenant-a"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "tenant-b"}},
+	}
+	clients := newTestClients(
+		objects,
+		[]resourceMapping{
+			{
+				GVR:   corev1.SchemeGroupVersion.WithResource("pods"),
+				GVK:   corev1.SchemeGroupVersion.WithKind("Pod"),
+				Scope: meta.RESTScopeNamespace,
+			},
+		},
+	)
+
+	fetcher := NewFetcher(clients, cfg)
+	items, err := fetcher.FetchResources(ctx, config.ObjectRule{
+		APIVersion:        "v1",
+		Kind:              "Pod",
+		Namespaces:        []string{"tenant-b"},
+		NamespaceSelector: "tier=gold",
+	})
+
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(objectNames(items)).To(gomega.Equal([]string{"b"}))
+}
+
+func TestFetcherResolvesNamespacePatterns(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+	cfg := &config.Config{
+		Namespaces:        []string{"team-*"},
+		ExcludeNamespaces: []string{"re:-sandbox$"},
+	}
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a-sandbox"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "platform"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team-a"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sandbox", Namespace: "team-a-sandbox"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "platform", Namespace: "platform"}},
+	}
+	clients := newTestClients(
+		objects,
+		[]resourceMapping{
+			{
+				GVR:   corev1.SchemeGroupVersion.WithResource("pods"),
+				GVK:   corev1.SchemeGroupVersion.WithKind("Pod"),
+				Scope: meta.RESTScopeNamespace,
+			},
+		},
+	)
+
+	fetcher := NewFetcher(clients, cfg)
+	items, err := fetcher.FetchResources(ctx, config.ObjectRule{APIVersion: "v1", Kind: "Pod"})
+
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(objectNames(items)).To(gomega.Equal([]string{"a"}))
+}
+
+func TestFetcherAppliesExcludeNamespacePatterns(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+	cfg := &config.Config{
+		ExcludeNamespaces: []string{"kube-*"},
+	}
+	clients := newTestClients(
+		[]runtime.Object{
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "lease", Namespace: "kube-node-lease"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default"}},
+		},
+		[]resourceMapping{
+			{
+				GVR:   corev1.SchemeGroupVersion.WithResource("pods"),
+				GVK:   corev1.SchemeGroupVersion.WithKind("Pod"),
+				Scope: meta.RESTScopeNamespace,
+			},
+		},
+	)
+
+	fetcher := NewFetcher(clients, cfg)
+	items, err := fetcher.FetchResources(ctx, config.ObjectRule{APIVersion: "v1", Kind: "Pod"})
+
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(objectNames(items)).To(gomega.Equal([]string{"worker"}))
+}
+
+func TestFetcherAppliesMultipleAndExcludedNamePatterns(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+	cfg := &config.Config{}
+	clients := newTestClients(
+		[]runtime.Object{
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "alloy-logs", Namespace: "default"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "alloy-logs-canary", Namespace: "default"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "grafana-agent", Namespace: "default"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "nodeexporter", Namespace: "default"}},
+		},
+		[]resourceMapping{
+			{
+				GVR:   corev1.SchemeGroupVersion.WithResource("pods"),
+				GVK:   corev1.SchemeGroupVersion.WithKind("Pod"),
+				Scope: meta.RESTScopeNamespace,
+			},
+		},
+	)
+
+	fetcher := NewFetcher(clients, cfg)
+	items, err := fetcher.FetchResources(ctx, config.ObjectRule{
+		APIVersion:          "v1",
+		Kind:                "Pod",
+		NamePatterns:        []string{"^alloy-", "^grafana-"},
+		ExcludeNamePatterns: []string{"-canary$"},
+	})
+
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(objectNames(items)).To(gomega.Equal([]string{"alloy-logs", "grafana-agent"}))
+}
+
 func objectNames(items []unstructured.Unstructured) []string {
 	var names []string
 	for _, item := range items {
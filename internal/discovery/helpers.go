@@ -3,9 +3,11 @@ package discovery
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/grafana/k8s-manifest-tail/internal/config"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -28,7 +30,15 @@ func ResolveMapping(mapper meta.RESTMapper, rule config.ObjectRule) (*meta.RESTM
 	return mapping, nil
 }
 
-// EffectiveNamespaces returns the namespaces a rule should target.
+// ListOptions returns the list and watch options that push a rule's selectors down to the API server.
+func ListOptions(rule config.ObjectRule) metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: strings.TrimSpace(rule.LabelSelector),
+		FieldSelector: strings.TrimSpace(rule.FieldSelector),
+	}
+}
+
+// EffectiveNamespaces returns the namespaces a rule should target. Entries may be literal names or patterns.
 func EffectiveNamespaces(rule config.ObjectRule, cfg *config.Config) []string {
 	if len(rule.Namespaces) > 0 {
 		return cloneAndDedupe(rule.Namespaces)
@@ -36,17 +46,12 @@ func EffectiveNamespaces(rule config.ObjectRule, cfg *config.Config) []string {
 	return cloneAndDedupe(cfg.Namespaces)
 }
 
-// ShouldExcludeNamespace reports whether the namespace should be ignored.
+// ShouldExcludeNamespace reports whether the namespace should be ignored. Exclusions may be literal names or patterns.
 func ShouldExcludeNamespace(candidate string, excludedNamespaces []string) bool {
 	if excludedNamespaces == nil {
 		return false
 	}
-	for _, namespace := range excludedNamespaces {
-		if candidate == namespace {
-			return true
-		}
-	}
-	return false
+	return config.NamespaceListMatches(excludedNamespaces, candidate)
 }
 
 func cloneAndDedupe(input []string) []string {
@@ -0,0 +1,89 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+// NamespaceResource identifies the core Namespace resource.
+var NamespaceResource = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// NamespaceMatcher decides which namespaces a rule should target when they cannot be known up front, either because
+// the rule has a namespaceSelector or because its included namespaces contain patterns.
+type NamespaceMatcher struct {
+	selector labels.Selector
+	included []string
+	excluded []string
+}
+
+// EffectiveNamespaceSelector returns the namespace selector a rule should use.
+func EffectiveNamespaceSelector(rule config.ObjectRule, cfg *config.Config) string {
+	if selector := strings.TrimSpace(rule.NamespaceSelector); selector != "" {
+		return selector
+	}
+	return strings.TrimSpace(cfg.NamespaceSelector)
+}
+
+// NewNamespaceMatcher builds a matcher for the rule, returning nil when the rule targets a fixed set of namespaces.
+func NewNamespaceMatcher(rule config.ObjectRule, cfg *config.Config) (*NamespaceMatcher, error) {
+	included := EffectiveNamespaces(rule, cfg)
+	raw := EffectiveNamespaceSelector(rule, cfg)
+	if raw == "" && !config.HasNamespacePatterns(included) {
+		return nil, nil
+	}
+	selector, err := labels.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse namespaceSelector %q: %w", raw, err)
+	}
+	return &NamespaceMatcher{
+		selector: selector,
+		included: included,
+		excluded: cfg.ExcludeNamespaces,
+	}, nil
+}
+
+// ListOptions returns the options used to list and watch matching namespaces.
+func (m *NamespaceMatcher) ListOptions() metav1.ListOptions {
+	return metav1.ListOptions{LabelSelector: m.selector.String()}
+}
+
+// Matches reports whether the namespace object should be targeted.
+func (m *NamespaceMatcher) Matches(namespace *unstructured.Unstructured) bool {
+	if namespace == nil || !m.selector.Matches(labels.Set(namespace.GetLabels())) {
+		return false
+	}
+	return m.allows(namespace.GetName())
+}
+
+// List returns the sorted names of the namespaces that currently match, along with the list's resource version.
+func (m *NamespaceMatcher) List(ctx context.Context, client dynamic.Interface) ([]string, string, error) {
+	list, err := client.Resource(NamespaceResource).List(ctx, m.ListOptions())
+	if err != nil {
+		return nil, "", fmt.Errorf("list namespaces matching %q: %w", m.selector.String(), err)
+	}
+	var names []string
+	for i := range list.Items {
+		if m.Matches(&list.Items[i]) {
+			names = append(names, list.Items[i].GetName())
+		}
+	}
+	slices.Sort(names)
+	return names, list.GetResourceVersion(), nil
+}
+
+func (m *NamespaceMatcher) allows(name string) bool {
+	if ShouldExcludeNamespace(name, m.excluded) {
+		return false
+	}
+	return len(m.included) == 0 || config.NamespaceListMatches(m.included, name)
+}
@@ -0,0 +1,463 @@
+package fieldpath
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Path addresses fields of a decoded JSON or YAML object. It is parsed from a JSONPath expression or a JSON Pointer.
+type Path struct {
+	expression string
+	segments   []segment
+}
+
+type segmentKind int
+
+const (
+	// fieldSegment selects a map key.
+	fieldSegment segmentKind = iota
+	// indexSegment selects an array element.
+	indexSegment
+	// wildcardSegment selects every map value or array element.
+	wildcardSegment
+	// filterSegment selects the array elements that match a predicate.
+	filterSegment
+	// pointerSegment is a JSON Pointer reference token: a map key, or an array index when it is numeric.
+	pointerSegment
+)
+
+type segment struct {
+	kind   segmentKind
+	name   string
+	index  int
+	filter *predicate
+}
+
+// predicate tests an array element: the value at path must exist and, when op is set, compare to value.
+type predicate struct {
+	path  []segment
+	op    string
+	value any
+}
+
+// String returns the expression the path was parsed from.
+func (p Path) String() string {
+	return p.expression
+}
+
+// Parse reads a field path. Expressions starting with "/" are JSON Pointers (RFC 6901), such as
+// "/metadata/annotations/example.com~1revision". Anything else is a JSONPath expression with an optional leading "$",
+// such as `metadata.annotations["deployment.kubernetes.io/revision"]`, `spec.containers[*].terminationMessagePath`, or
+// `spec.containers[?(@.name == "app")].env`. JSONPath expressions support fields, quoted keys, indexes, the "*"
+// wildcard, and filters that test whether a field exists or equals (==) or differs from (!=) a literal.
+func Parse(expression string) (Path, error) {
+	var segments []segment
+	var err error
+	if strings.HasPrefix(expression, "/") {
+		segments, err = parsePointer(expression)
+	} else {
+		segments, err = parseJSONPath(expression)
+	}
+	if err != nil {
+		return Path{}, fmt.Errorf("invalid field path %q: %w", expression, err)
+	}
+	if len(segments) == 0 {
+		return Path{}, fmt.Errorf("invalid field path %q: selects the whole object", expression)
+	}
+	return Path{expression: expression, segments: segments}, nil
+}
+
+func parsePointer(expression string) ([]segment, error) {
+	var segments []segment
+	for _, token := range strings.Split(expression[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		segments = append(segments, segment{kind: pointerSegment, name: token})
+	}
+	return segments, nil
+}
+
+func parseJSONPath(expression string) ([]segment, error) {
+	p := &parser{input: strings.TrimPrefix(expression, "$")}
+	segments, err := p.segments(true)
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.input[p.pos:], p.pos)
+	}
+	return segments, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) skipSpaces() {
+	for !p.done() && p.peek() == ' ' {
+		p.pos++
+	}
+}
+
+// segments reads fields, bracket selectors, and wildcards until the input ends or a character that cannot continue a
+// path, such as a space or ")" inside a filter. A leading field may omit its dot when leadingField is set.
+func (p *parser) segments(leadingField bool) ([]segment, error) {
+	var segments []segment
+	for !p.done() {
+		switch c := p.peek(); {
+		case c == '.':
+			p.pos++
+			if p.peek() == '*' {
+				p.pos++
+				segments = append(segments, segment{kind: wildcardSegment})
+				continue
+			}
+			name := p.name()
+			if name == "" {
+				return nil, fmt.Errorf("missing field name at offset %d", p.pos)
+			}
+			segments = append(segments, segment{kind: fieldSegment, name: name})
+		case c == '[':
+			selector, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, selector)
+		case leadingField && len(segments) == 0:
+			name := p.name()
+			if name == "" {
+				return nil, fmt.Errorf("unexpected %q at offset %d", c, p.pos)
+			}
+			segments = append(segments, segment{kind: fieldSegment, name: name})
+		default:
+			return segments, nil
+		}
+	}
+	return segments, nil
+}
+
+func (p *parser) name() string {
+	start := p.pos
+	for !p.done() && !strings.ContainsRune(".[]()=!<> '\"", rune(p.peek())) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *parser) bracket() (segment, error) {
+	p.pos++ // [
+	p.skipSpaces()
+	var result segment
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		result = segment{kind: wildcardSegment}
+	case c == '"' || c == '\'':
+		key, err := p.quoted()
+		if err != nil {
+			return segment{}, err
+		}
+		result = segment{kind: fieldSegment, name: key}
+	case c == '?':
+		filter, err := p.filter()
+		if err != nil {
+			return segment{}, err
+		}
+		result = segment{kind: filterSegment, filter: filter}
+	default:
+		start := p.pos
+		for !p.done() && p.peek() >= '0' && p.peek() <= '9' {
+			p.pos++
+		}
+		index, err := strconv.Atoi(p.input[start:p.pos])
+		if err != nil {
+			return segment{}, fmt.Errorf("expected an index, a quoted key, \"*\", or a filter at offset %d", start)
+		}
+		result = segment{kind: indexSegment, index: index}
+	}
+	p.skipSpaces()
+	if p.peek() != ']' {
+		return segment{}, fmt.Errorf("missing \"]\" at offset %d", p.pos)
+	}
+	p.pos++
+	return result, nil
+}
+
+func (p *parser) quoted() (string, error) {
+	quote := p.peek()
+	start := p.pos
+	p.pos++
+	var value strings.Builder
+	for !p.done() {
+		c := p.peek()
+		p.pos++
+		switch {
+		case c == quote:
+			return value.String(), nil
+		case c == '\\' && !p.done():
+			value.WriteByte(p.peek())
+			p.pos++
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated string at offset %d", start)
+}
+
+func (p *parser) filter() (*predicate, error) {
+	if !strings.HasPrefix(p.input[p.pos:], "?(@") {
+		return nil, fmt.Errorf("expected a filter such as ?(@.name == \"value\") at offset %d", p.pos)
+	}
+	p.pos += len("?(@")
+	path, err := p.segments(false)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("filter at offset %d must test a field of the element", p.pos)
+	}
+	if slices.ContainsFunc(path, func(s segment) bool { return s.kind != fieldSegment && s.kind != indexSegment }) {
+		return nil, fmt.Errorf("filter at offset %d may only use fields and indexes", p.pos)
+	}
+	result := &predicate{path: path}
+	p.skipSpaces()
+	for _, op := range []string{"==", "!="} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			p.pos += len(op)
+			p.skipSpaces()
+			value, err := p.literal()
+			if err != nil {
+				return nil, err
+			}
+			result.op = op
+			result.value = value
+			p.skipSpaces()
+			break
+		}
+	}
+	if p.peek() != ')' {
+		return nil, fmt.Errorf("missing \")\" at offset %d", p.pos)
+	}
+	p.pos++
+	return result, nil
+}
+
+func (p *parser) literal() (any, error) {
+	if c := p.peek(); c == '"' || c == '\'' {
+		return p.quoted()
+	}
+	start := p.pos
+	for !p.done() && p.peek() != ')' && p.peek() != ' ' {
+		p.pos++
+	}
+	word := p.input[start:p.pos]
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	number, err := strconv.ParseFloat(word, 64)
+	if err != nil {
+		return nil, fmt.Errorf("expected a quoted string, a number, true, false, or null at offset %d", start)
+	}
+	return number, nil
+}
+
+// Remove deletes every field the path selects from obj and returns how many were removed. Removed array elements are
+// taken out of their array, shifting the elements after them.
+func (p Path) Remove(obj map[string]any) int {
+	return p.RemoveMatching(obj, func(any) bool { return true })
+}
+
+// RemoveMatching deletes the fields the path selects whose values satisfy match, and returns how many were removed.
+func (p Path) RemoveMatching(obj map[string]any, match func(value any) bool) int {
+	_, removed := remove(obj, p.segments, match)
+	return removed
+}
+
+// Find returns the values of the fields the path selects in obj. Maps and slices are returned as is, so changes to their
+// contents show up in obj.
+func (p Path) Find(obj map[string]any) []any {
+	return find(obj, p.segments)
+}
+
+func find(value any, segments []segment) []any {
+	if len(segments) == 0 {
+		return []any{value}
+	}
+	current, rest := segments[0], segments[1:]
+	var found []any
+	switch typed := value.(type) {
+	case map[string]any:
+		switch current.kind {
+		case fieldSegment, pointerSegment:
+			if next, ok := typed[current.name]; ok {
+				found = find(next, rest)
+			}
+		case wildcardSegment:
+			for _, key := range slices.Sorted(maps.Keys(typed)) {
+				found = append(found, find(typed[key], rest)...)
+			}
+		}
+	case []any:
+		for i, item := range typed {
+			if selects(current, i, item) {
+				found = append(found, find(item, rest)...)
+			}
+		}
+	}
+	return found
+}
+
+// selects reports whether an array segment selects the element at index.
+func selects(s segment, index int, item any) bool {
+	switch s.kind {
+	case indexSegment:
+		return s.index == index
+	case pointerSegment:
+		return s.name == strconv.Itoa(index)
+	case wildcardSegment:
+		return true
+	case filterSegment:
+		return s.filter.matches(item)
+	default:
+		return false
+	}
+}
+
+// remove deletes the fields selected by segments below value, returning the updated value, which differs from value
+// only when an array shrank.
+func remove(value any, segments []segment, match func(any) bool) (any, int) {
+	current, rest := segments[0], segments[1:]
+	switch typed := value.(type) {
+	case map[string]any:
+		return typed, removeFromMap(typed, current, rest, match)
+	case []any:
+		return removeFromArray(typed, current, rest, match)
+	default:
+		return value, 0
+	}
+}
+
+func removeFromMap(obj map[string]any, current segment, rest []segment, match func(any) bool) int {
+	var keys []string
+	switch current.kind {
+	case fieldSegment, pointerSegment:
+		if _, ok := obj[current.name]; ok {
+			keys = []string{current.name}
+		}
+	case wildcardSegment:
+		for key := range obj {
+			keys = append(keys, key)
+		}
+	}
+
+	removed := 0
+	for _, key := range keys {
+		if len(rest) == 0 {
+			if match(obj[key]) {
+				delete(obj, key)
+				removed++
+			}
+			continue
+		}
+		updated, count := remove(obj[key], rest, match)
+		obj[key] = updated
+		removed += count
+	}
+	return removed
+}
+
+func removeFromArray(items []any, current segment, rest []segment, match func(any) bool) ([]any, int) {
+	selected := make([]bool, len(items))
+	for i, item := range items {
+		selected[i] = selects(current, i, item)
+	}
+
+	if len(rest) == 0 {
+		kept := items[:0:0]
+		for i, item := range items {
+			if !selected[i] || !match(item) {
+				kept = append(kept, item)
+			}
+		}
+		return kept, len(items) - len(kept)
+	}
+	removed := 0
+	for i := range items {
+		if selected[i] {
+			updated, count := remove(items[i], rest, match)
+			items[i] = updated
+			removed += count
+		}
+	}
+	return items, removed
+}
+
+func (f *predicate) matches(item any) bool {
+	value, found := lookup(item, f.path)
+	if !found {
+		return false
+	}
+	switch f.op {
+	case "==":
+		return equal(value, f.value)
+	case "!=":
+		return !equal(value, f.value)
+	default:
+		return true
+	}
+}
+
+func lookup(value any, segments []segment) (any, bool) {
+	for _, s := range segments {
+		switch typed := value.(type) {
+		case map[string]any:
+			next, ok := typed[s.name]
+			if !ok || s.kind != fieldSegment {
+				return nil, false
+			}
+			value = next
+		case []any:
+			if s.kind != indexSegment || s.index >= len(typed) {
+				return nil, false
+			}
+			value = typed[s.index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func equal(value, literal any) bool {
+	if number, ok := literal.(float64); ok {
+		switch typed := value.(type) {
+		case int64:
+			return float64(typed) == number
+		case int:
+			return float64(typed) == number
+		case float64:
+			return typed == number
+		}
+		return false
+	}
+	return value == literal
+}
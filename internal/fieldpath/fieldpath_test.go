@@ -0,0 +1,199 @@
+package fieldpath
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+)
+
+func decode(t *testing.T, data string) map[string]any {
+	t.Helper()
+	var obj map[string]any
+	if err := yaml.Unmarshal([]byte(data), &obj); err != nil {
+		t.Fatalf("decode object: %v", err)
+	}
+	return obj
+}
+
+const deployment = `
+metadata: {name: api, annotations: {deployment.kubernetes.io/revision: "3", team: edge}}
+spec:
+  containers:
+    - {name: app, terminationMessagePath: /dev/termination-log, ports: [{containerPort: 8080}]}
+    - {name: sidecar, terminationMessagePath: /dev/termination-log, ports: [{containerPort: 9090}]}
+`
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		path     string
+		removed  int
+		expected string
+	}{
+		{
+			name:    "quoted key",
+			path:    `metadata.annotations["deployment.kubernetes.io/revision"]`,
+			removed: 1,
+			expected: `
+metadata: {name: api, annotations: {team: edge}}
+spec:
+  containers:
+    - {name: app, terminationMessagePath: /dev/termination-log, ports: [{containerPort: 8080}]}
+    - {name: sidecar, terminationMessagePath: /dev/termination-log, ports: [{containerPort: 9090}]}
+`,
+		},
+		{
+			name:    "wildcard array elements",
+			path:    `$.spec.containers[*].terminationMessagePath`,
+			removed: 2,
+			expected: `
+metadata: {name: api, annotations: {deployment.kubernetes.io/revision: "3", team: edge}}
+spec:
+  containers:
+    - {name: app, ports: [{containerPort: 8080}]}
+    - {name: sidecar, ports: [{containerPort: 9090}]}
+`,
+		},
+		{
+			name:    "filtered array elements",
+			path:    `spec.containers[?(@.name == 'sidecar')]`,
+			removed: 1,
+			expected: `
+metadata: {name: api, annotations: {deployment.kubernetes.io/revision: "3", team: edge}}
+spec:
+  containers:
+    - {name: app, terminationMessagePath: /dev/termination-log, ports: [{containerPort: 8080}]}
+`,
+		},
+		{
+			name:    "numeric filter",
+			path:    `spec.containers[*].ports[?(@.containerPort != 8080)]`,
+			removed: 1,
+			expected: `
+metadata: {name: api, annotations: {deployment.kubernetes.io/revision: "3", team: edge}}
+spec:
+  containers:
+    - {name: app, terminationMessagePath: /dev/termination-log, ports: [{containerPort: 8080}]}
+    - {name: sidecar, terminationMessagePath: /dev/termination-log, ports: []}
+`,
+		},
+		{
+			name:    "index and map wildcard",
+			path:    `spec.containers[0].*`,
+			removed: 3,
+			expected: `
+metadata: {name: api, annotations: {deployment.kubernetes.io/revision: "3", team: edge}}
+spec:
+  containers:
+    - {}
+    - {name: sidecar, terminationMessagePath: /dev/termination-log, ports: [{containerPort: 9090}]}
+`,
+		},
+		{
+			name:    "JSON pointer",
+			path:    `/metadata/annotations/deployment.kubernetes.io~1revision`,
+			removed: 1,
+			expected: `
+metadata: {name: api, annotations: {team: edge}}
+spec:
+  containers:
+    - {name: app, terminationMessagePath: /dev/termination-log, ports: [{containerPort: 8080}]}
+    - {name: sidecar, terminationMessagePath: /dev/termination-log, ports: [{containerPort: 9090}]}
+`,
+		},
+		{
+			name:    "JSON pointer array index",
+			path:    `/spec/containers/1`,
+			removed: 1,
+			expected: `
+metadata: {name: api, annotations: {deployment.kubernetes.io/revision: "3", team: edge}}
+spec:
+  containers:
+    - {name: app, terminationMessagePath: /dev/termination-log, ports: [{containerPort: 8080}]}
+`,
+		},
+		{
+			name:     "missing field",
+			path:     `spec.strategy.rollingUpdate`,
+			removed:  0,
+			expected: deployment,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewWithT(t)
+
+			path, err := Parse(test.path)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(path.String()).To(gomega.Equal(test.path))
+
+			obj := decode(t, deployment)
+			g.Expect(path.Remove(obj)).To(gomega.Equal(test.removed))
+			g.Expect(obj).To(gomega.Equal(decode(t, test.expected)))
+		})
+	}
+}
+
+func TestRemoveMatching(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	path, err := Parse(`spec.containers[*].ports[*]`)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	obj := decode(t, deployment)
+	removed := path.RemoveMatching(obj, func(value any) bool {
+		return value.(map[string]any)["containerPort"] == float64(9090)
+	})
+	g.Expect(removed).To(gomega.Equal(1))
+	g.Expect(obj).To(gomega.Equal(decode(t, `
+metadata: {name: api, annotations: {deployment.kubernetes.io/revision: "3", team: edge}}
+spec:
+  containers:
+    - {name: app, terminationMessagePath: /dev/termination-log, ports: [{containerPort: 8080}]}
+    - {name: sidecar, terminationMessagePath: /dev/termination-log, ports: []}
+`)))
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	obj := decode(t, deployment)
+	for expression, expected := range map[string][]any{
+		`spec.containers[*].name`:                       {"app", "sidecar"},
+		`spec.containers[?(@.name == "sidecar")].ports`: {[]any{map[string]any{"containerPort": float64(9090)}}},
+		`/spec/containers/0/name`:                       {"app"},
+		`metadata.*`:                                    {map[string]any{"deployment.kubernetes.io/revision": "3", "team": "edge"}, "api"},
+		`spec.volumes`:                                  nil,
+	} {
+		path, err := Parse(expression)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(path.Find(obj)).To(gomega.Equal(expected), expression)
+	}
+}
+
+func TestParseRejectsInvalidPaths(t *testing.T) {
+	t.Parallel()
+
+	for path, message := range map[string]string{
+		"":                                `invalid field path "": selects the whole object`,
+		"$":                               `invalid field path "$": selects the whole object`,
+		"metadata.":                       `invalid field path "metadata.": missing field name at offset 9`,
+		`metadata.annotations["team`:      `invalid field path "metadata.annotations[\"team": unterminated string at offset 21`,
+		"spec.containers[x]":              `invalid field path "spec.containers[x]": expected an index, a quoted key, "*", or a filter at offset 16`,
+		"spec.containers[0":               `invalid field path "spec.containers[0": missing "]" at offset 17`,
+		"spec.containers[?(@.name = 1)]":  `invalid field path "spec.containers[?(@.name = 1)]": missing ")" at offset 25`,
+		"spec.containers[?(@.name == x)]": `invalid field path "spec.containers[?(@.name == x)]": expected a quoted string, a number, true, false, or null at offset 28`,
+		"spec.containers[?(@[*])]":        `invalid field path "spec.containers[?(@[*])]": filter at offset 22 may only use fields and indexes`,
+		"spec containers":                 `invalid field path "spec containers": unexpected " containers" at offset 4`,
+	} {
+		_, err := Parse(path)
+		gomega.NewWithT(t).Expect(err).To(gomega.MatchError(message), path)
+	}
+}
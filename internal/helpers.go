@@ -6,15 +6,18 @@ import (
 )
 
 func FormatQuotedList(elements []string) string {
-	if len(elements) == 0 {
-		return ""
-	}
-
 	quoted := make([]string, len(elements))
 	for i, element := range elements {
 		quoted[i] = fmt.Sprintf("%q", element)
 	}
+	return FormatList(quoted)
+}
 
+// FormatList joins already formatted elements into a readable "a, b, or c" list.
+func FormatList(quoted []string) string {
+	if len(quoted) == 0 {
+		return ""
+	}
 	if len(quoted) == 1 {
 		return quoted[0]
 	}
@@ -36,3 +36,11 @@ func TestFormatQuotedList_ThreeElements(t *testing.T) {
 	list := []string{"a", "b", "c"}
 	g.Expect(FormatQuotedList(list)).To(gomega.Equal(`"a", "b", or "c"`))
 }
+
+func TestFormatList_LeavesElementsUnquoted(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	list := []string{`"a"`, "the regular expression \"^b$\"", `"c"`}
+	g.Expect(FormatList(list)).To(gomega.Equal(`"a", the regular expression "^b$", or "c"`))
+}
@@ -6,6 +6,7 @@ import (
 	"k8s.io/client-go/discovery"
 	memdiscovery "k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/openapi"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
@@ -15,8 +16,11 @@ import (
 
 // Clients bundles the interfaces needed to query Kubernetes APIs.
 type Clients struct {
-	Dynamic dynamic.Interface
-	Mapper  meta.RESTMapper
+	Dynamic   dynamic.Interface
+	Mapper    meta.RESTMapper
+	Discovery discovery.DiscoveryInterface
+	// OpenAPI serves the OpenAPI v3 documents without caching them, so that callers see new versions.
+	OpenAPI openapi.Client
 }
 
 // Provider creates Kubernetes API clients.
@@ -47,11 +51,14 @@ func (p *defaultProvider) Provide(cfg *config.Config) (*Clients, error) {
 		return nil, fmt.Errorf("create discovery client: %w", err)
 	}
 
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memdiscovery.NewMemCacheClient(discoveryClient))
+	cachedDiscovery := memdiscovery.NewMemCacheClient(discoveryClient)
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery)
 
 	return &Clients{
-		Dynamic: dynamicClient,
-		Mapper:  mapper,
+		Dynamic:   dynamicClient,
+		Mapper:    mapper,
+		Discovery: cachedDiscovery,
+		OpenAPI:   discoveryClient.OpenAPIV3(),
 	}, nil
 }
 
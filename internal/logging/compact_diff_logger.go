@@ -28,20 +28,18 @@ func (l *CompactDiffLogger) Log(diff *manifest.Diff) {
 		action = "deleted"
 	}
 
-	if target.GetNamespace() == "" {
-		telemetry.Info(
-			l.logger,
-			fmt.Sprintf("Object %s: %s %s", action, target.GetKind(), target.GetName()),
-			log.String("action", action),
-			log.String(fmt.Sprintf("k8s.%s.name", strings.ToLower(target.GetKind())), target.GetName()),
-		)
-	} else {
-		telemetry.Info(
-			l.logger,
-			fmt.Sprintf("Object %s: %s %s/%s", action, target.GetKind(), target.GetNamespace(), target.GetName()),
-			log.String("action", action),
-			log.String(fmt.Sprintf("k8s.%s.name", strings.ToLower(target.GetKind())), target.GetName()),
-			log.String("k8s.namespace.name", target.GetNamespace()),
-		)
+	attributes := []log.KeyValue{
+		log.String("action", action),
+		log.String(fmt.Sprintf("k8s.%s.name", strings.ToLower(target.GetKind())), target.GetName()),
 	}
+	message := fmt.Sprintf("Object %s: %s %s", action, target.GetKind(), target.GetName())
+	if target.GetNamespace() != "" {
+		attributes = append(attributes, log.String("k8s.namespace.name", target.GetNamespace()))
+		message = fmt.Sprintf("Object %s: %s %s/%s", action, target.GetKind(), target.GetNamespace(), target.GetName())
+	}
+	if diff.Redactions > 0 {
+		attributes = append(attributes, log.Int("redactions", diff.Redactions))
+		message = fmt.Sprintf("%s (%d value(s) redacted)", message, diff.Redactions)
+	}
+	telemetry.Info(l.logger, message, attributes...)
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/log"
 
 	"github.com/grafana/k8s-manifest-tail/internal/config"
 	"github.com/grafana/k8s-manifest-tail/internal/logging"
@@ -85,3 +86,28 @@ func TestCompactDiffLoggerLogsClusterScopedCreation(t *testing.T) {
 	body := stub.records[0].Body().AsString()
 	g.Expect(body).To(gomega.ContainSubstring("Object created: ClusterRole my-cluster-role"))
 }
+
+func TestCompactDiffLoggerReportsRedactions(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	stub := &StubLogger{}
+	logger := logging.NewDiffLogger(config.LoggingConfig{LogDiffs: config.LogDiffsCompact}, stub)
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName("api")
+
+	logger.Log(&manifest.Diff{Current: obj, Redactions: 2})
+
+	g.Expect(stub.records).To(gomega.HaveLen(1))
+	g.Expect(stub.records[0].Body().AsString()).To(gomega.Equal("Object created: ConfigMap default/api (2 value(s) redacted)"))
+	var redactions int64
+	stub.records[0].WalkAttributes(func(kv log.KeyValue) bool {
+		if kv.Key == "redactions" {
+			redactions = kv.Value.AsInt64()
+		}
+		return true
+	})
+	g.Expect(redactions).To(gomega.Equal(int64(2)))
+}
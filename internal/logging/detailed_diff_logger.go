@@ -25,6 +25,8 @@ type DetailedDiffReport struct {
 	Action    string      `json:"action"`
 	Previous  interface{} `json:"previous,omitempty"`
 	Current   interface{} `json:"current,omitempty"`
+	// Redactions is the number of values redacted in the current object by counting filters.
+	Redactions int `json:"redactions,omitempty"`
 }
 
 // Log implements DiffLogger.
@@ -33,7 +35,7 @@ func (l *DetailedDiffLogger) Log(diff *manifest.Diff) {
 		return
 	}
 
-	payload := &DetailedDiffReport{}
+	payload := &DetailedDiffReport{Redactions: diff.Redactions}
 	if diff.Previous == nil {
 		payload.Kind = diff.Current.GetKind()
 		payload.Name = diff.Current.GetName()
@@ -0,0 +1,260 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"go.opentelemetry.io/otel/log"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/openapi"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+// FilterEnvironment holds what filters may need from the cluster.
+type FilterEnvironment struct {
+	// OpenAPI serves the cluster's OpenAPI v3 documents. Without it, pruneDefaults relies on the documents it cached
+	// earlier.
+	OpenAPI openapi.Client
+	// Logger receives the warnings of filters, such as OpenAPI documents that cannot be fetched. Without it, they are
+	// not reported.
+	Logger log.Logger
+}
+
+// NewFilter builds the built-in filter selected by the supplied configuration. A filter limited to some kinds leaves
+// objects of other kinds untouched.
+func NewFilter(spec config.FilterConfig, env FilterEnvironment) (Filter, error) {
+	filter, err := newBuiltInFilter(spec, env)
+	if err != nil {
+		return nil, err
+	}
+	if len(spec.Kinds) > 0 {
+		return kindFilter{spec: spec, filter: filter}, nil
+	}
+	return filter, nil
+}
+
+func newBuiltInFilter(spec config.FilterConfig, env FilterEnvironment) (Filter, error) {
+	switch spec.Name {
+	case config.FilterRemoveStatus:
+		return RemoveStatusFilter{}, nil
+	case config.FilterRemoveMetadataFields:
+		return RemoveMetadataFieldsFilter{Fields: spec.Fields}, nil
+	case config.FilterRedactEnvValues:
+		redactor, err := NewRedactor(spec)
+		if err != nil {
+			return nil, err
+		}
+		return NewRedactEnvValuesFilter(redactor, spec.ContainerPaths)
+	case config.FilterRemoveFields:
+		return NewRemoveFieldsFilter(spec.Paths)
+	case config.FilterRedactData:
+		redactor, err := NewRedactor(spec)
+		if err != nil {
+			return nil, err
+		}
+		return NewRedactDataFilter(redactor, spec.KeyPatterns)
+	case config.FilterNeat:
+		return NewNeatFilter(spec.EffectiveRuleSet(), spec.Rules)
+	case config.FilterPruneDefaults:
+		return NewPruneDefaultsFilter(env, spec.CacheDirectory), nil
+	case config.FilterScrubSecrets:
+		redactor, err := NewRedactor(spec)
+		if err != nil {
+			return nil, err
+		}
+		return NewScrubSecretsFilter(redactor, spec.EffectiveDetectors(), spec.Patterns)
+	case config.FilterJQ:
+		return NewJQFilter(spec.Program)
+	case config.FilterPlugin:
+		timeout, err := spec.EffectiveTimeout()
+		if err != nil {
+			return nil, err
+		}
+		return NewPluginFilter(spec.Command, timeout)
+	case config.FilterWasm:
+		memoryLimit, err := spec.EffectiveMemoryLimit()
+		if err != nil {
+			return nil, err
+		}
+		timeout, err := spec.EffectiveTimeout()
+		if err != nil {
+			return nil, err
+		}
+		return NewWasmFilter(spec.Modules, memoryLimit, timeout)
+	default:
+		return nil, fmt.Errorf("unknown filter %q", spec.Name)
+	}
+}
+
+// kindFilter applies a filter only to the kinds its configuration lists.
+type kindFilter struct {
+	spec   config.FilterConfig
+	filter Filter
+}
+
+// Apply runs the wrapped filter when the object's kind is listed.
+func (f kindFilter) Apply(obj *unstructured.Unstructured) error {
+	if obj == nil || !f.spec.AppliesTo(obj.GetKind()) {
+		return nil
+	}
+	return f.filter.Apply(obj)
+}
+
+// ApplyCounting runs the wrapped filter when the object's kind is listed, and returns how many values it redacted.
+func (f kindFilter) ApplyCounting(obj *unstructured.Unstructured) (int, error) {
+	return f.ApplyRule(config.ObjectRule{}, obj)
+}
+
+// ApplyRule runs the wrapped filter for the objects of a rule when the object's kind is listed.
+func (f kindFilter) ApplyRule(rule config.ObjectRule, obj *unstructured.Unstructured) (int, error) {
+	if obj == nil || !f.spec.AppliesTo(obj.GetKind()) {
+		return 0, nil
+	}
+	return applyFilter(f.filter, rule, obj)
+}
+
+// NewFilters builds a filter chain, keeping the order of the supplied configuration.
+func NewFilters(specs []config.FilterConfig, env FilterEnvironment) ([]Filter, error) {
+	filters := make([]Filter, 0, len(specs))
+	for i, spec := range specs {
+		filter, err := NewFilter(spec, env)
+		if err != nil {
+			return nil, fmt.Errorf("build filter %d: %w", i+1, err)
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// RuleFilters applies the filter chain and the patches configured for each rule before delegating to the next
+// processor. The chain is looked up in the configuration passed to each call, so reloaded filter settings take effect
+// immediately. Rules with the same chain share the built filters.
+type RuleFilters struct {
+	next Processor
+	env  FilterEnvironment
+
+	mu     sync.Mutex
+	chains map[string]ruleChain
+}
+
+// ruleChain holds the filters and the patches of a rule, built from its configuration.
+type ruleChain struct {
+	filters []Filter
+	patches []Filter
+}
+
+// NewRuleFilters constructs a processor that applies each rule's filters before invoking next.
+func NewRuleFilters(next Processor, env FilterEnvironment) *RuleFilters {
+	return &RuleFilters{
+		next:   next,
+		env:    env,
+		chains: make(map[string]ruleChain),
+	}
+}
+
+// Process applies the rule's filters, then its patches, and passes the object to the next processor.
+// The diff reports how many values the filters redacted. An object dropped by a filter is not passed on; its stored
+// copy is deleted instead. An object that a patch does not apply to is not passed on either, and the returned error
+// matches ErrSkipObject.
+func (p *RuleFilters) Process(rule config.ObjectRule, obj *unstructured.Unstructured, cfg *config.Config) (*Diff, error) {
+	redactions, err := p.apply(rule, obj, cfg)
+	if errors.Is(err, ErrDropObject) {
+		return nil, p.next.Delete(rule, obj, cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	diff, err := p.next.Process(rule, obj, cfg)
+	if diff != nil {
+		diff.Redactions += redactions
+	}
+	return diff, err
+}
+
+// Delete applies the rule's filters before delegating deletion to the next processor.
+func (p *RuleFilters) Delete(rule config.ObjectRule, obj *unstructured.Unstructured, cfg *config.Config) error {
+	if _, err := p.apply(rule, obj, cfg); err != nil && !errors.Is(err, ErrDropObject) && !errors.Is(err, ErrSkipObject) {
+		return err
+	}
+	return p.next.Delete(rule, obj, cfg)
+}
+
+func (p *RuleFilters) apply(rule config.ObjectRule, obj *unstructured.Unstructured, cfg *config.Config) (int, error) {
+	chain, err := p.chainFor(cfg.GetFilters(rule), rule.Patches)
+	if err != nil {
+		return 0, err
+	}
+	redactions := 0
+	for _, filter := range chain.filters {
+		count, err := applyFilter(filter, rule, obj)
+		if errors.Is(err, ErrDropObject) {
+			return 0, err
+		}
+		if err != nil {
+			return 0, fmt.Errorf("apply filter: %w", err)
+		}
+		redactions += count
+	}
+	for i, patch := range chain.patches {
+		if err := patch.Apply(obj); err != nil {
+			return 0, fmt.Errorf("apply patch %d: %w", i+1, err)
+		}
+	}
+	return redactions, nil
+}
+
+// Close stops the plugins and WebAssembly runtimes of every chain built so far.
+func (p *RuleFilters) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var errs []error
+	for key, chain := range p.chains {
+		errs = append(errs, closeFilters(chain.filters))
+		delete(p.chains, key)
+	}
+	return errors.Join(errs...)
+}
+
+// closeFilters closes the filters that hold resources, such as plugins and WebAssembly runtimes.
+func closeFilters(filters []Filter) error {
+	var errs []error
+	for _, filter := range filters {
+		if limited, ok := filter.(kindFilter); ok {
+			filter = limited.filter
+		}
+		if closer, ok := filter.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *RuleFilters) chainFor(specs []config.FilterConfig, patches []config.PatchConfig) (ruleChain, error) {
+	key, err := json.Marshal(struct {
+		Filters []config.FilterConfig
+		Patches []config.PatchConfig
+	}{specs, patches})
+	if err != nil {
+		return ruleChain{}, fmt.Errorf("encode filters: %w", err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if chain, ok := p.chains[string(key)]; ok {
+		return chain, nil
+	}
+	filters, err := NewFilters(specs, p.env)
+	if err != nil {
+		return ruleChain{}, err
+	}
+	patchFilters, err := NewPatchFilters(patches)
+	if err != nil {
+		return ruleChain{}, err
+	}
+	chain := ruleChain{filters: filters, patches: patchFilters}
+	p.chains[string(key)] = chain
+	return chain, nil
+}
@@ -0,0 +1,70 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+func TestNewFilterBuildsEveryBuiltInFilter(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	for _, name := range config.FilterNames() {
+		filter, err := NewFilter(config.FilterConfig{Name: name, Paths: []string{"metadata.labels"}, Program: ".", Command: []string{"plugin"}, Modules: []string{testWasmModule(t)}}, FilterEnvironment{})
+		g.Expect(err).NotTo(gomega.HaveOccurred(), name)
+		g.Expect(filter).NotTo(gomega.BeNil(), name)
+	}
+
+	_, err := NewFilters([]config.FilterConfig{{Name: config.FilterRemoveStatus}, {Name: "unknown"}}, FilterEnvironment{})
+	g.Expect(err).To(gomega.MatchError(`build filter 2: unknown filter "unknown"`))
+}
+
+func TestRuleFiltersApplyEachRulesChain(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	next := &stubProcessor{}
+	processor := NewRuleFilters(next, FilterEnvironment{})
+	cfg := &config.Config{}
+
+	pod := newUnstructured("v1", "Pod", "default", "api")
+	pod.Object["status"] = map[string]interface{}{"phase": "Running"}
+	_, err := processor.Process(config.ObjectRule{Kind: "Pod"}, pod, cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(next.lastObj.Object).NotTo(gomega.HaveKey("status"))
+
+	autoscalerRule := config.ObjectRule{Kind: "HorizontalPodAutoscaler", Filters: []config.FilterConfig{
+		{Name: config.FilterRemoveMetadataFields, Fields: []string{"labels"}},
+	}}
+	autoscaler := newUnstructured("autoscaling/v2", "HorizontalPodAutoscaler", "default", "api")
+	autoscaler.Object["status"] = map[string]interface{}{"currentReplicas": int64(3)}
+	autoscaler.SetLabels(map[string]string{"app": "api"})
+	autoscaler.SetUID("uid-123")
+	_, err = processor.Process(autoscalerRule, autoscaler, cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(next.lastObj.Object).To(gomega.HaveKey("status"))
+	g.Expect(next.lastObj.GetLabels()).To(gomega.BeEmpty())
+	g.Expect(string(next.lastObj.GetUID())).To(gomega.Equal("uid-123"))
+}
+
+func TestRuleFiltersFollowTheSuppliedConfiguration(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	next := &stubProcessor{}
+	processor := NewRuleFilters(next, FilterEnvironment{})
+	rule := config.ObjectRule{Kind: "Pod"}
+
+	obj := newUnstructured("v1", "Pod", "default", "api")
+	obj.Object["status"] = map[string]interface{}{"phase": "Running"}
+	_, err := processor.Process(rule, obj, &config.Config{Filters: []config.FilterConfig{}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(next.lastObj.Object).To(gomega.HaveKey("status"))
+
+	_, err = processor.Process(rule, obj, &config.Config{Filters: []config.FilterConfig{{Name: config.FilterRemoveStatus}}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(next.lastObj.Object).NotTo(gomega.HaveKey("status"))
+}
@@ -0,0 +1,96 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/itchyny/gojq"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+// JQFilter rewrites objects with a jq program. A program that returns null, or nothing, drops the object. An object the
+// program fails on, or turns into anything but a single object, is not written: the returned error matches
+// ErrSkipObject.
+type JQFilter struct {
+	code *gojq.Code
+}
+
+// NewJQFilter compiles a jq program into a filter.
+func NewJQFilter(program string) (JQFilter, error) {
+	code, err := config.CompileJQ(program)
+	if err != nil {
+		return JQFilter{}, err
+	}
+	return JQFilter{code: code}, nil
+}
+
+// Apply replaces the object with the program's output, or returns ErrDropObject when the output is null or empty.
+func (f JQFilter) Apply(obj *unstructured.Unstructured) error {
+	if obj == nil {
+		return nil
+	}
+	// gojq only handles the number types of encoding/json, while unstructured objects hold int64, so the object is
+	// passed through JSON on the way in and out.
+	input, err := toJQValue(obj.Object)
+	if err != nil {
+		return err
+	}
+
+	iter := f.code.Run(input)
+	output, ok := iter.Next()
+	if !ok || output == nil {
+		return ErrDropObject
+	}
+	if err, isErr := output.(error); isErr {
+		return skipError{err: fmt.Errorf("run jq program: %w", err)}
+	}
+	if _, more := iter.Next(); more {
+		return skipError{err: errors.New("jq program returned more than one value")}
+	}
+	if _, isObject := output.(map[string]any); !isObject {
+		return skipError{err: fmt.Errorf("jq program returned %s, not an object", describeJQValue(output))}
+	}
+
+	encoded, err := gojq.Marshal(output)
+	if err != nil {
+		return fmt.Errorf("encode jq output: %w", err)
+	}
+	var transformed map[string]interface{}
+	if err := utiljson.Unmarshal(encoded, &transformed); err != nil {
+		return fmt.Errorf("decode jq output: %w", err)
+	}
+	obj.Object = transformed
+	return nil
+}
+
+func toJQValue(obj map[string]interface{}) (any, error) {
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("encode object for jq: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("decode object for jq: %w", err)
+	}
+	return value, nil
+}
+
+func describeJQValue(value any) string {
+	switch value.(type) {
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	default:
+		return "a number"
+	}
+}
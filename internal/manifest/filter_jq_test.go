@@ -0,0 +1,112 @@
+package manifest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+func TestJQFilterTransformsObjects(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter, err := NewFilter(config.FilterConfig{
+		Name:    config.FilterJQ,
+		Program: `del(.spec.replicas) | .metadata.labels.tier = "web"`,
+	}, FilterEnvironment{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	obj := newUnstructured("apps/v1", "Deployment", "prod", "frontend")
+	obj.Object["spec"] = map[string]interface{}{"replicas": int64(3), "revisionHistoryLimit": int64(10)}
+	g.Expect(filter.Apply(obj)).To(gomega.Succeed())
+
+	g.Expect(obj.GetLabels()).To(gomega.Equal(map[string]string{"tier": "web"}))
+	// Numbers keep the types of unstructured objects.
+	g.Expect(obj.Object["spec"]).To(gomega.Equal(map[string]interface{}{"revisionHistoryLimit": int64(10)}))
+}
+
+func TestJQFilterDropsObjects(t *testing.T) {
+	t.Parallel()
+
+	for _, program := range []string{`null`, `empty`, `select(.metadata.name != "frontend")`} {
+		t.Run(program, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewWithT(t)
+
+			filter, err := NewJQFilter(program)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			err = filter.Apply(newUnstructured("apps/v1", "Deployment", "prod", "frontend"))
+			g.Expect(err).To(gomega.MatchError(ErrDropObject))
+		})
+	}
+}
+
+func TestJQFilterRejectsInvalidOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`.metadata.name`:    "jq program returned a string, not an object",
+		`.[]`:               "jq program returned more than one value",
+		`error("no thank")`: "run jq program: error: no thank",
+	}
+
+	for program, message := range tests {
+		t.Run(program, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewWithT(t)
+
+			filter, err := NewJQFilter(program)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			err = filter.Apply(newUnstructured("apps/v1", "Deployment", "prod", "frontend"))
+			g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(message)))
+		})
+	}
+}
+
+func TestRuleFiltersDeleteDroppedObjects(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	rule := config.ObjectRule{Kind: "Deployment"}
+	processor := NewRuleFilters(NewWriter(config.OutputConfig{Directory: dir, Format: config.OutputFormatYAML}), FilterEnvironment{})
+	path := filepath.Join(dir, "Deployment", "prod", "frontend.yaml")
+
+	cfg := &config.Config{Filters: []config.FilterConfig{{Name: config.FilterJQ, Program: `select(.metadata.labels.skip != "true")`}}}
+	_, err := processor.Process(rule, newUnstructured("apps/v1", "Deployment", "prod", "frontend"), cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(path).To(gomega.BeAnExistingFile())
+
+	dropped := newUnstructured("apps/v1", "Deployment", "prod", "frontend")
+	dropped.SetLabels(map[string]string{"skip": "true"})
+	diff, err := processor.Process(rule, dropped, cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(diff).To(gomega.BeNil())
+	g.Expect(path).NotTo(gomega.BeAnExistingFile())
+}
+
+func TestRuleFiltersSkipObjectsTheJQProgramFailsOn(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	rule := config.ObjectRule{Kind: "Deployment"}
+	processor := NewRuleFilters(NewWriter(config.OutputConfig{Directory: dir, Format: config.OutputFormatYAML}), FilterEnvironment{})
+	cfg := &config.Config{Filters: []config.FilterConfig{{Name: config.FilterJQ, Program: `.spec.replicas += 1`}}}
+
+	broken := newUnstructured("apps/v1", "Deployment", "prod", "broken")
+	broken.Object["spec"] = map[string]interface{}{"replicas": "three"}
+	_, err := processor.Process(rule, broken, cfg)
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(filepath.Join(dir, "Deployment", "prod", "broken.yaml")).NotTo(gomega.BeAnExistingFile())
+
+	working := newUnstructured("apps/v1", "Deployment", "prod", "working")
+	working.Object["spec"] = map[string]interface{}{"replicas": int64(3)}
+	_, err = processor.Process(rule, working, cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(filepath.Join(dir, "Deployment", "prod", "working.yaml")).To(gomega.BeAnExistingFile())
+}
@@ -1,17 +1,27 @@
 package manifest
 
-import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
 
 // RemoveMetadataFieldsFilter strips server-populated metadata entries.
-type RemoveMetadataFieldsFilter struct{}
+type RemoveMetadataFieldsFilter struct {
+	// Fields lists the metadata fields to remove. Empty means config.DefaultRemovedMetadataFields.
+	Fields []string
+}
 
 // Apply removes known noisy metadata fields.
-func (RemoveMetadataFieldsFilter) Apply(obj *unstructured.Unstructured) error {
+func (f RemoveMetadataFieldsFilter) Apply(obj *unstructured.Unstructured) error {
 	if obj == nil {
 		return nil
 	}
 
-	fields := []string{"managedFields", "resourceVersion", "uid", "selfLink", "generation", "creationTimestamp"}
+	fields := f.Fields
+	if len(fields) == 0 {
+		fields = config.DefaultRemovedMetadataFields
+	}
 	for _, field := range fields {
 		unstructured.RemoveNestedField(obj.Object, "metadata", field)
 	}
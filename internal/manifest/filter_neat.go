@@ -0,0 +1,171 @@
+package manifest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/k8s-manifest-tail/internal/fieldpath"
+)
+
+// anyKind keys the neat rules that apply to every kind.
+const anyKind = "*"
+
+// neatRule removes fields that a controller or the API server set, rather than the person who applied the object.
+type neatRule func(obj map[string]interface{})
+
+// neatRuleSets holds the curated rules of each version in config.NeatRuleSets, keyed by kind or anyKind. A released
+// version must not change, since users pin it to keep their output stable; add a new version instead.
+var neatRuleSets = map[string]map[string][]neatRule{
+	"v1": {
+		anyKind: {
+			removePaths(`metadata.annotations["kubectl.kubernetes.io/last-applied-configuration"]`),
+		},
+		"Deployment": {
+			removePaths(`metadata.annotations["deployment.kubernetes.io/revision"]`),
+		},
+		"ReplicaSet": {
+			removePaths(
+				`metadata.annotations["deployment.kubernetes.io/revision"]`,
+				`metadata.annotations["deployment.kubernetes.io/revision-history"]`,
+				`metadata.annotations["deployment.kubernetes.io/desired-replicas"]`,
+				`metadata.annotations["deployment.kubernetes.io/max-replicas"]`,
+			),
+		},
+		"DaemonSet": {
+			removePaths(`metadata.annotations["deprecated.daemonset.template.generation"]`),
+		},
+		"Service": {
+			removeAllocatedClusterIPs,
+			removePaths(`spec.ipFamilies`),
+			removeIfEquals("SingleStack", "spec", "ipFamilyPolicy"),
+		},
+		"PersistentVolumeClaim": {
+			removeAnnotationPrefixes("volume.kubernetes.io/", "volume.beta.kubernetes.io/", "pv.kubernetes.io/"),
+		},
+		"PersistentVolume": {
+			removeAnnotationPrefixes("pv.kubernetes.io/"),
+		},
+		"Job": {
+			removePaths(
+				`metadata.labels["controller-uid"]`,
+				`metadata.labels["batch.kubernetes.io/controller-uid"]`,
+				`spec.selector.matchLabels["controller-uid"]`,
+				`spec.selector.matchLabels["batch.kubernetes.io/controller-uid"]`,
+				`spec.template.metadata.labels["controller-uid"]`,
+				`spec.template.metadata.labels["batch.kubernetes.io/controller-uid"]`,
+			),
+		},
+		"Namespace": {
+			removePaths(`metadata.labels["kubernetes.io/metadata.name"]`, `spec.finalizers`),
+		},
+	},
+}
+
+// NeatFilter removes controller-managed annotations and server-assigned fields, so that manifests look like what a
+// person would have applied.
+type NeatFilter struct {
+	rules map[string][]neatRule
+}
+
+// NewNeatFilter builds a filter from a curated rule set, extended with field paths keyed by kind or "*".
+func NewNeatFilter(ruleSet string, extra map[string][]string) (NeatFilter, error) {
+	curated, ok := neatRuleSets[ruleSet]
+	if !ok {
+		return NeatFilter{}, fmt.Errorf("unknown neat rule set %q", ruleSet)
+	}
+	rules := make(map[string][]neatRule, len(curated)+len(extra))
+	for kind, kindRules := range curated {
+		rules[kind] = slices.Clone(kindRules)
+	}
+	for kind, expressions := range extra {
+		paths, err := parsePaths(expressions)
+		if err != nil {
+			return NeatFilter{}, fmt.Errorf("rules for %s: %w", kind, err)
+		}
+		rules[kind] = append(rules[kind], removeParsedPaths(paths))
+	}
+	return NeatFilter{rules: rules}, nil
+}
+
+// Apply runs the rules for every kind and for the object's kind.
+func (f NeatFilter) Apply(obj *unstructured.Unstructured) error {
+	if obj == nil {
+		return nil
+	}
+	for _, rule := range f.rules[anyKind] {
+		rule(obj.Object)
+	}
+	for _, rule := range f.rules[obj.GetKind()] {
+		rule(obj.Object)
+	}
+	return nil
+}
+
+func parsePaths(expressions []string) ([]fieldpath.Path, error) {
+	paths := make([]fieldpath.Path, 0, len(expressions))
+	for _, expression := range expressions {
+		path, err := fieldpath.Parse(expression)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// removePaths parses curated paths, which are known to be valid.
+func removePaths(expressions ...string) neatRule {
+	paths, err := parsePaths(expressions)
+	if err != nil {
+		panic(err)
+	}
+	return removeParsedPaths(paths)
+}
+
+func removeParsedPaths(paths []fieldpath.Path) neatRule {
+	return func(obj map[string]interface{}) {
+		for _, path := range paths {
+			path.Remove(obj)
+		}
+	}
+}
+
+func removeIfEquals(value string, fields ...string) neatRule {
+	return func(obj map[string]interface{}) {
+		if current, found, _ := unstructured.NestedString(obj, fields...); found && current == value {
+			unstructured.RemoveNestedField(obj, fields...)
+		}
+	}
+}
+
+func removeAnnotationPrefixes(prefixes ...string) neatRule {
+	return func(obj map[string]interface{}) {
+		annotations, found, _ := unstructured.NestedMap(obj, "metadata", "annotations")
+		if !found {
+			return
+		}
+		changed := false
+		for key := range annotations {
+			if slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(key, prefix) }) {
+				delete(annotations, key)
+				changed = true
+			}
+		}
+		if changed {
+			_ = unstructured.SetNestedMap(obj, annotations, "metadata", "annotations")
+		}
+	}
+}
+
+// removeAllocatedClusterIPs removes the cluster IPs the API server allocated, keeping "None", which marks a headless
+// Service that a person asked for.
+func removeAllocatedClusterIPs(obj map[string]interface{}) {
+	if clusterIP, _, _ := unstructured.NestedString(obj, "spec", "clusterIP"); clusterIP == "None" {
+		return
+	}
+	unstructured.RemoveNestedField(obj, "spec", "clusterIP")
+	unstructured.RemoveNestedField(obj, "spec", "clusterIPs")
+}
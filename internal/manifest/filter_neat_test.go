@@ -0,0 +1,211 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+func decodeManifest(t *testing.T, manifest string) *unstructured.Unstructured {
+	t.Helper()
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	return obj
+}
+
+func TestNeatRuleSetsMatchConfiguration(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	g.Expect(neatRuleSets).To(gomega.HaveLen(len(config.NeatRuleSets)))
+	for _, version := range config.NeatRuleSets {
+		g.Expect(neatRuleSets).To(gomega.HaveKey(version))
+	}
+}
+
+func TestNeatFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		manifest string
+		expected string
+	}{
+		{
+			name: "deployment annotations",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  annotations:
+    deployment.kubernetes.io/revision: "7"
+    kubectl.kubernetes.io/last-applied-configuration: '{"kind":"Deployment"}'
+    team: edge
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  annotations:
+    team: edge
+`,
+		},
+		{
+			name: "service cluster IPs",
+			manifest: `
+apiVersion: v1
+kind: Service
+metadata: {name: api}
+spec:
+  clusterIP: 10.0.0.12
+  clusterIPs: [10.0.0.12]
+  ipFamilies: [IPv4]
+  ipFamilyPolicy: SingleStack
+  ports: [{port: 80}]
+`,
+			expected: `
+apiVersion: v1
+kind: Service
+metadata: {name: api}
+spec:
+  ports: [{port: 80}]
+`,
+		},
+		{
+			name: "headless service",
+			manifest: `
+apiVersion: v1
+kind: Service
+metadata: {name: api}
+spec:
+  clusterIP: None
+  clusterIPs: [None]
+  ipFamilies: [IPv4, IPv6]
+  ipFamilyPolicy: PreferDualStack
+`,
+			expected: `
+apiVersion: v1
+kind: Service
+metadata: {name: api}
+spec:
+  clusterIP: None
+  clusterIPs: [None]
+  ipFamilyPolicy: PreferDualStack
+`,
+		},
+		{
+			name: "persistent volume claim annotations",
+			manifest: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  annotations:
+    pv.kubernetes.io/bind-completed: "yes"
+    volume.kubernetes.io/storage-provisioner: ebs.csi.aws.com
+    volume.beta.kubernetes.io/storage-provisioner: ebs.csi.aws.com
+    backup: daily
+`,
+			expected: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  annotations:
+    backup: daily
+`,
+		},
+		{
+			name: "job controller labels",
+			manifest: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  labels: {batch.kubernetes.io/controller-uid: abc, app: migrate}
+spec:
+  selector:
+    matchLabels: {batch.kubernetes.io/controller-uid: abc}
+  template:
+    metadata:
+      labels: {batch.kubernetes.io/controller-uid: abc, app: migrate}
+`,
+			expected: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  labels: {app: migrate}
+spec:
+  selector:
+    matchLabels: {}
+  template:
+    metadata:
+      labels: {app: migrate}
+`,
+		},
+	}
+
+	filter, err := NewNeatFilter(config.LatestNeatRuleSet, nil)
+	if err != nil {
+		t.Fatalf("build filter: %v", err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewWithT(t)
+
+			obj := decodeManifest(t, test.manifest)
+			g.Expect(filter.Apply(obj)).To(gomega.Succeed())
+			g.Expect(obj.Object).To(gomega.Equal(decodeManifest(t, test.expected).Object))
+		})
+	}
+}
+
+func TestNeatFilterExtendedFromConfiguration(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter, err := NewFilter(config.FilterConfig{Name: config.FilterNeat, Rules: map[string][]string{
+		"*":       {`metadata.annotations["argocd.argoproj.io/tracking-id"]`},
+		"Service": {`spec.ports[*].nodePort`},
+	}}, FilterEnvironment{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	service := decodeManifest(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  annotations: {argocd.argoproj.io/tracking-id: "api:v1/Service:default/api"}
+spec:
+  clusterIP: 10.0.0.12
+  ports: [{port: 80, nodePort: 30080}]
+`)
+	g.Expect(filter.Apply(service)).To(gomega.Succeed())
+	g.Expect(service.Object).To(gomega.Equal(decodeManifest(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  annotations: {}
+spec:
+  ports: [{port: 80}]
+`).Object))
+
+	// The curated rules are not shared with other filters.
+	plain, err := NewNeatFilter(config.LatestNeatRuleSet, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(plain.rules["Service"]).To(gomega.HaveLen(len(neatRuleSets[config.LatestNeatRuleSet]["Service"])))
+
+	_, err = NewNeatFilter("v0", nil)
+	g.Expect(err).To(gomega.MatchError(`unknown neat rule set "v0"`))
+}
@@ -0,0 +1,105 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+// ErrSkipObject marks errors that concern a single object, such as a patch that does not apply to it. The object is
+// skipped and the error is reported as a warning, while the other objects are still processed.
+var ErrSkipObject = errors.New("object skipped")
+
+// skipError reports why an object was skipped, and matches ErrSkipObject.
+type skipError struct {
+	err error
+}
+
+func (e skipError) Error() string {
+	return e.err.Error()
+}
+
+func (e skipError) Unwrap() []error {
+	return []error{ErrSkipObject, e.err}
+}
+
+// PatchFilter applies a JSON patch or a strategic merge patch to objects.
+type PatchFilter struct {
+	spec     config.PatchConfig
+	document []byte
+	patch    jsonpatch.Patch
+}
+
+// NewPatchFilter decodes a patch.
+func NewPatchFilter(spec config.PatchConfig) (PatchFilter, error) {
+	document, err := spec.Document()
+	if err != nil {
+		return PatchFilter{}, err
+	}
+	filter := PatchFilter{spec: spec, document: document}
+	if spec.IsJSONPatch() {
+		if filter.patch, err = jsonpatch.DecodePatch(document); err != nil {
+			return PatchFilter{}, fmt.Errorf("invalid JSON patch: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+// Apply patches objects of the kinds the patch lists. A patch that does not apply returns an error matching
+// ErrSkipObject.
+func (f PatchFilter) Apply(obj *unstructured.Unstructured) error {
+	if obj == nil || !f.spec.AppliesTo(obj.GetKind()) {
+		return nil
+	}
+	original, err := obj.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("encode object: %w", err)
+	}
+
+	var patched []byte
+	if f.patch != nil {
+		patched, err = f.patch.Apply(original)
+	} else {
+		patched, err = f.mergePatch(obj, original)
+	}
+	if err != nil {
+		return skipError{err: err}
+	}
+
+	var result map[string]interface{}
+	if err := utiljson.Unmarshal(patched, &result); err != nil {
+		return fmt.Errorf("decode patched object: %w", err)
+	}
+	obj.Object = result
+	return nil
+}
+
+// mergePatch applies a strategic merge patch to kinds built into Kubernetes, which know how to merge their lists, and
+// a JSON merge patch to other kinds.
+func (f PatchFilter) mergePatch(obj *unstructured.Unstructured, original []byte) ([]byte, error) {
+	typed, err := scheme.Scheme.New(obj.GroupVersionKind())
+	if err != nil {
+		return jsonpatch.MergePatch(original, f.document)
+	}
+	return strategicpatch.StrategicMergePatch(original, f.document, typed)
+}
+
+// NewPatchFilters builds the patches of a rule, keeping their order.
+func NewPatchFilters(specs []config.PatchConfig) ([]Filter, error) {
+	filters := make([]Filter, 0, len(specs))
+	for i, spec := range specs {
+		filter, err := NewPatchFilter(spec)
+		if err != nil {
+			return nil, fmt.Errorf("build patch %d: %w", i+1, err)
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
@@ -0,0 +1,151 @@
+package manifest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+func TestPatchFilterAppliesJSONPatches(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter, err := NewPatchFilter(config.PatchConfig{JSON: `
+- {op: remove, path: /spec/replicas}
+- {op: add, path: /metadata/labels, value: {managed-by: argocd}}
+`})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	obj := decodeManifest(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api, namespace: prod}
+spec: {replicas: 3, revisionHistoryLimit: 10}
+`)
+	g.Expect(filter.Apply(obj)).To(gomega.Succeed())
+	g.Expect(obj.Object).To(gomega.Equal(map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "api", "namespace": "prod", "labels": map[string]interface{}{"managed-by": "argocd"}},
+		"spec":       map[string]interface{}{"revisionHistoryLimit": int64(10)},
+	}))
+}
+
+func TestPatchFilterMergesBuiltInKindsStrategically(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter, err := NewPatchFilter(config.PatchConfig{StrategicMerge: `
+spec:
+  replicas: null
+  template:
+    spec:
+      containers:
+        - name: sidecar
+          image: proxy:2
+`})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	obj := decodeManifest(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api, namespace: prod}
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+        - {name: app, image: app:1}
+        - {name: sidecar, image: proxy:1}
+`)
+	g.Expect(filter.Apply(obj)).To(gomega.Succeed())
+	g.Expect(obj.Object).To(gomega.Equal(decodeManifest(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api, namespace: prod}
+spec:
+  template:
+    spec:
+      containers:
+        - {name: app, image: app:1}
+        - {name: sidecar, image: proxy:2}
+`).Object))
+}
+
+func TestPatchFilterMergesCustomResources(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter, err := NewPatchFilter(config.PatchConfig{StrategicMerge: `{spec: {size: null, steps: [{name: build}]}}`})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	obj := decodeManifest(t, `
+apiVersion: example.com/v1
+kind: Widget
+metadata: {name: gadget}
+spec:
+  size: 3
+  color: blue
+  steps: [{name: checkout}, {name: test}]
+`)
+	g.Expect(filter.Apply(obj)).To(gomega.Succeed())
+	// Without a strategy, lists are replaced as a whole.
+	g.Expect(obj.Object["spec"]).To(gomega.Equal(map[string]interface{}{
+		"color": "blue",
+		"steps": []interface{}{map[string]interface{}{"name": "build"}},
+	}))
+}
+
+func TestPatchFilterSkipsObjectsItDoesNotApplyTo(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter, err := NewPatchFilter(config.PatchConfig{JSON: `[{op: remove, path: /spec/replicas}]`})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	err = filter.Apply(newUnstructured("apps/v1", "Deployment", "prod", "api"))
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(err.Error()).NotTo(gomega.ContainSubstring(ErrSkipObject.Error()))
+
+	limited, err := NewPatchFilter(config.PatchConfig{Kinds: []string{"StatefulSet"}, JSON: `[{op: remove, path: /spec/replicas}]`})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(limited.Apply(newUnstructured("apps/v1", "Deployment", "prod", "api"))).To(gomega.Succeed())
+}
+
+func TestRuleFiltersApplyPatchesAfterFilters(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	processor := NewRuleFilters(NewWriter(config.OutputConfig{Directory: dir, Format: config.OutputFormatYAML}), FilterEnvironment{})
+	rule := config.ObjectRule{
+		Kind: "Deployment",
+		// The patch runs after removeStatus, so the status it adds is kept.
+		Patches: []config.PatchConfig{{JSON: `[{op: remove, path: /spec/replicas}, {op: add, path: /status, value: {}}]`}},
+	}
+	cfg := &config.Config{Filters: []config.FilterConfig{{Name: config.FilterRemoveStatus}}}
+
+	obj := decodeManifest(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api, namespace: prod}
+spec: {replicas: 3}
+status: {readyReplicas: 3}
+`)
+	diff, err := processor.Process(rule, obj, cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(diff.Current.Object).To(gomega.Equal(decodeManifest(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api, namespace: prod}
+spec: {}
+status: {}
+`).Object))
+
+	_, err = processor.Process(rule, newUnstructured("apps/v1", "Deployment", "prod", "web"), cfg)
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(err.Error()).To(gomega.HavePrefix("apply patch 1: "))
+	g.Expect(filepath.Join(dir, "Deployment", "prod", "web.yaml")).NotTo(gomega.BeAnExistingFile())
+}
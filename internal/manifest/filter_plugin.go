@@ -0,0 +1,287 @@
+package manifest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+// pluginStopGrace is how long a plugin may take to exit after its stdin is closed before it is killed.
+const pluginStopGrace = 5 * time.Second
+
+// errPluginExited reports a plugin that exited, or closed its stdout, before it answered a request.
+var errPluginExited = errors.New("plugin exited")
+
+// pluginRequest is the line written to a plugin for each object.
+type pluginRequest struct {
+	ID     uint64                 `json:"id"`
+	Rule   pluginRule             `json:"rule"`
+	Object map[string]interface{} `json:"object"`
+}
+
+// pluginRule identifies the rule that collected an object.
+type pluginRule struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+}
+
+// pluginResponse is the line a plugin writes back for each request. Exactly one of Object, Drop, and Error is set.
+type pluginResponse struct {
+	ID         uint64          `json:"id"`
+	Object     json.RawMessage `json:"object,omitempty"`
+	Drop       bool            `json:"drop,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Redactions int             `json:"redactions,omitempty"`
+
+	// err reports a line that is not a response.
+	err error
+}
+
+// PluginFilter sends each object to a long-lived child process, which returns the filtered object. Requests and
+// responses are single lines of JSON on the plugin's stdin and stdout; its stderr is passed through. The plugin is
+// started on first use, and started again when it crashes. Requests are sent one at a time.
+//
+// An object the plugin cannot filter, because it reports an error, does not answer in time, or keeps crashing, is not
+// written: the returned error matches ErrSkipObject.
+type PluginFilter struct {
+	command []string
+	timeout time.Duration
+
+	mu      sync.Mutex
+	process *pluginProcess
+	nextID  uint64
+	closed  bool
+}
+
+// NewPluginFilter builds a filter that runs command, waiting up to timeout for each response.
+func NewPluginFilter(command []string, timeout time.Duration) (*PluginFilter, error) {
+	if len(command) == 0 {
+		return nil, errors.New("plugin command is empty")
+	}
+	return &PluginFilter{command: command, timeout: timeout}, nil
+}
+
+// Apply sends the object to the plugin without naming a rule.
+func (f *PluginFilter) Apply(obj *unstructured.Unstructured) error {
+	_, err := f.ApplyRule(config.ObjectRule{}, obj)
+	return err
+}
+
+// ApplyCounting sends the object to the plugin without naming a rule, and returns how many values it redacted.
+func (f *PluginFilter) ApplyCounting(obj *unstructured.Unstructured) (int, error) {
+	return f.ApplyRule(config.ObjectRule{}, obj)
+}
+
+// ApplyRule sends the object and its rule to the plugin and replaces the object with the plugin's answer. It returns
+// how many values the plugin reported redacting, or ErrDropObject when the plugin drops the object.
+func (f *PluginFilter) ApplyRule(rule config.ObjectRule, obj *unstructured.Unstructured) (int, error) {
+	if obj == nil {
+		return 0, nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return 0, skipError{err: errors.New("plugin is stopped")}
+	}
+
+	f.nextID++
+	request, err := json.Marshal(pluginRequest{
+		ID:     f.nextID,
+		Rule:   pluginRule{APIVersion: rule.APIVersion, Kind: rule.Kind},
+		Object: obj.Object,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("encode plugin request: %w", err)
+	}
+
+	// A plugin that crashed is started again, and the request sent once more, so that one crash does not skip an
+	// object. A request that crashes the plugin twice is given up.
+	var response pluginResponse
+	for attempt := 1; ; attempt++ {
+		response, err = f.exchange(f.nextID, request)
+		if err == nil {
+			break
+		}
+		f.stop(0)
+		if !errors.Is(err, errPluginExited) || attempt == 2 {
+			return 0, skipError{err: err}
+		}
+	}
+
+	switch {
+	case response.Error != "":
+		return 0, skipError{err: fmt.Errorf("plugin reported: %s", response.Error)}
+	case response.Drop:
+		return 0, ErrDropObject
+	case len(response.Object) == 0 || string(response.Object) == "null":
+		return 0, skipError{err: errors.New("plugin returned no object")}
+	}
+	var filtered map[string]interface{}
+	if err := utiljson.Unmarshal(response.Object, &filtered); err != nil {
+		return 0, skipError{err: fmt.Errorf("decode plugin object: %w", err)}
+	}
+	obj.Object = filtered
+	return response.Redactions, nil
+}
+
+// exchange sends one request and waits for the response with the same ID, starting the plugin when it is not running.
+func (f *PluginFilter) exchange(id uint64, request []byte) (pluginResponse, error) {
+	if f.process == nil {
+		process, err := startPlugin(f.command)
+		if err != nil {
+			return pluginResponse{}, err
+		}
+		f.process = process
+	}
+	process := f.process
+
+	timer := time.NewTimer(f.timeout)
+	defer timer.Stop()
+	// The request is written while the response is awaited, so that a plugin that stops reading its stdin cannot hold
+	// a large request past the timeout. Stopping the plugin ends the write.
+	written := make(chan error, 1)
+	go func() {
+		_, err := process.stdin.Write(append(request, '\n'))
+		written <- err
+	}()
+	for {
+		select {
+		case err := <-written:
+			if err != nil {
+				return pluginResponse{}, fmt.Errorf("%w: %s", errPluginExited, process.exitStatus())
+			}
+			written = nil
+		case response := <-process.responses:
+			if response.err != nil {
+				return pluginResponse{}, response.err
+			}
+			if response.ID == id {
+				return response, nil
+			}
+		case <-process.exited:
+			return pluginResponse{}, fmt.Errorf("%w: %s", errPluginExited, process.exitStatus())
+		case <-timer.C:
+			return pluginResponse{}, fmt.Errorf("plugin did not answer within %s", f.timeout)
+		}
+	}
+}
+
+// stop ends the plugin, if it is running, killing it when it has not exited within grace. The next request starts it
+// again.
+func (f *PluginFilter) stop(grace time.Duration) {
+	if f.process != nil {
+		f.process.stop(grace)
+		f.process = nil
+	}
+}
+
+// Close stops the plugin, giving it a moment to exit after its stdin is closed. Later requests fail.
+func (f *PluginFilter) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	f.stop(pluginStopGrace)
+	return nil
+}
+
+// pluginProcess is a running plugin.
+type pluginProcess struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan pluginResponse
+	// exited is closed once the plugin has exited; err then holds its exit status.
+	exited chan struct{}
+	err    error
+	// done is closed when the plugin is stopped, so that its reader does not wait for requests that never come.
+	done chan struct{}
+}
+
+func startPlugin(command []string) (*pluginProcess, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("start plugin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("start plugin: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start plugin: %w", err)
+	}
+
+	process := &pluginProcess{
+		cmd:       cmd,
+		stdin:     stdin,
+		responses: make(chan pluginResponse),
+		exited:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go process.read(stdout)
+	return process, nil
+}
+
+// read passes every line the plugin writes to its stdout on as a response, until the plugin closes it.
+func (p *pluginProcess) read(stdout io.Reader) {
+	defer func() {
+		p.err = p.cmd.Wait()
+		close(p.exited)
+	}()
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var response pluginResponse
+			if decodeErr := json.Unmarshal(line, &response); decodeErr != nil {
+				response.err = fmt.Errorf("decode plugin response: %w", decodeErr)
+			}
+			select {
+			case p.responses <- response:
+			case <-p.done:
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// stop closes the plugin's stdin, which asks it to exit, and kills it when it has not exited within grace.
+func (p *pluginProcess) stop(grace time.Duration) {
+	close(p.done)
+	_ = p.stdin.Close()
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-p.exited:
+	case <-timer.C:
+		_ = p.cmd.Process.Kill()
+		<-p.exited
+	}
+}
+
+// exitStatus describes how the plugin exited, once it has.
+func (p *pluginProcess) exitStatus() string {
+	select {
+	case <-p.exited:
+	case <-time.After(time.Second):
+		return "still running"
+	}
+	if p.err != nil {
+		return p.err.Error()
+	}
+	return "exit status 0"
+}
@@ -0,0 +1,198 @@
+package manifest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+// TestMain runs the test binary as the plugin of the plugin filter tests when it is started with "test-plugin".
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == "test-plugin" {
+		runTestPlugin(os.Args[2])
+		os.Exit(0)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "test-plugin-stalled" {
+		// Never reads its stdin, so that large requests fill the pipe.
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+	code := m.Run()
+	removeTestWasmModule()
+	os.Exit(code)
+}
+
+// runTestPlugin answers each request according to the object's name, recording its process ID in an annotation.
+// "crash-once" crashes the plugin unless the marker file exists, which it creates.
+func runTestPlugin(marker string) {
+	scanner := bufio.NewScanner(os.Stdin)
+	encoder := json.NewEncoder(os.Stdout)
+	for scanner.Scan() {
+		var request struct {
+			ID     uint64                 `json:"id"`
+			Rule   map[string]string      `json:"rule"`
+			Object map[string]interface{} `json:"object"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		metadata := request.Object["metadata"].(map[string]interface{})
+		response := map[string]interface{}{"id": request.ID}
+		switch metadata["name"] {
+		case "crash":
+			os.Exit(3)
+		case "crash-once":
+			if _, err := os.Stat(marker); err != nil {
+				_ = os.WriteFile(marker, nil, 0o600)
+				os.Exit(3)
+			}
+		case "hang":
+			time.Sleep(time.Minute)
+		case "fail":
+			response["error"] = "cannot redact"
+		case "drop":
+			response["drop"] = true
+		}
+		if len(response) == 1 {
+			metadata["annotations"] = map[string]interface{}{
+				"plugin/rule": request.Rule["kind"],
+				"plugin/pid":  strconv.Itoa(os.Getpid()),
+			}
+			response["object"] = request.Object
+			response["redactions"] = 1
+		}
+		_ = encoder.Encode(response)
+	}
+}
+
+func newTestPluginFilter(t *testing.T, timeout time.Duration) *PluginFilter {
+	t.Helper()
+	filter, err := NewPluginFilter([]string{os.Args[0], "test-plugin", filepath.Join(t.TempDir(), "crashed")}, timeout)
+	if err != nil {
+		t.Fatalf("build plugin filter: %v", err)
+	}
+	t.Cleanup(func() { _ = filter.Close() })
+	return filter
+}
+
+func TestPluginFilterExchangesObjects(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter := newTestPluginFilter(t, 10*time.Second)
+	rule := config.ObjectRule{APIVersion: "apps/v1", Kind: "Deployment"}
+
+	first := newUnstructured("apps/v1", "Deployment", "prod", "api")
+	first.Object["spec"] = map[string]interface{}{"replicas": int64(3)}
+	redactions, err := filter.ApplyRule(rule, first)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(redactions).To(gomega.Equal(1))
+	g.Expect(first.GetAnnotations()).To(gomega.HaveKeyWithValue("plugin/rule", "Deployment"))
+	g.Expect(first.Object["spec"]).To(gomega.Equal(map[string]interface{}{"replicas": int64(3)}))
+
+	// The same process answers every request.
+	second := newUnstructured("apps/v1", "Deployment", "prod", "web")
+	_, err = filter.ApplyRule(rule, second)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(second.GetAnnotations()["plugin/pid"]).To(gomega.Equal(first.GetAnnotations()["plugin/pid"]))
+}
+
+func TestPluginFilterReportsErrorsAndDrops(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter := newTestPluginFilter(t, 10*time.Second)
+
+	err := filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "fail"))
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(err).To(gomega.MatchError("plugin reported: cannot redact"))
+
+	err = filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "drop"))
+	g.Expect(err).To(gomega.MatchError(ErrDropObject))
+}
+
+func TestPluginFilterRestartsCrashedPlugins(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter := newTestPluginFilter(t, 10*time.Second)
+
+	before := newUnstructured("v1", "ConfigMap", "prod", "api")
+	g.Expect(filter.Apply(before)).To(gomega.Succeed())
+
+	// The request is sent again to a new process.
+	crashed := newUnstructured("v1", "ConfigMap", "prod", "crash-once")
+	g.Expect(filter.Apply(crashed)).To(gomega.Succeed())
+	g.Expect(crashed.GetAnnotations()["plugin/pid"]).NotTo(gomega.Equal(before.GetAnnotations()["plugin/pid"]))
+
+	err := filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "crash"))
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(err).To(gomega.MatchError("plugin exited: exit status 3"))
+
+	g.Expect(filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "api"))).To(gomega.Succeed())
+}
+
+func TestPluginFilterStopsPluginsThatDoNotAnswer(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter := newTestPluginFilter(t, 200*time.Millisecond)
+
+	err := filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "hang"))
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(err).To(gomega.MatchError("plugin did not answer within 200ms"))
+
+	g.Expect(filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "api"))).To(gomega.Succeed())
+}
+
+func TestPluginFilterTimesOutWritingToPluginsThatDoNotRead(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter, err := NewPluginFilter([]string{os.Args[0], "test-plugin-stalled"}, 200*time.Millisecond)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	t.Cleanup(func() { _ = filter.Close() })
+
+	obj := newUnstructured("v1", "ConfigMap", "prod", "large")
+	obj.Object["data"] = map[string]interface{}{"payload": strings.Repeat("x", 1<<20)}
+
+	started := time.Now()
+	err = filter.Apply(obj)
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(err).To(gomega.MatchError("plugin did not answer within 200ms"))
+	g.Expect(time.Since(started)).To(gomega.BeNumerically("<", 5*time.Second))
+}
+
+func TestRuleFiltersClosePlugins(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	processor := NewRuleFilters(NewWriter(config.OutputConfig{Directory: t.TempDir(), Format: config.OutputFormatYAML}), FilterEnvironment{})
+	cfg := &config.Config{Filters: []config.FilterConfig{{
+		Name:    config.FilterPlugin,
+		Kinds:   []string{"ConfigMap"},
+		Command: []string{os.Args[0], "test-plugin", filepath.Join(t.TempDir(), "crashed")},
+	}}}
+	rule := config.ObjectRule{APIVersion: "v1", Kind: "ConfigMap"}
+
+	diff, err := processor.Process(rule, newUnstructured("v1", "ConfigMap", "prod", "api"), cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(diff.Redactions).To(gomega.Equal(1))
+	chain, err := processor.chainFor(cfg.Filters, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(processor.Close()).To(gomega.Succeed())
+	err = chain.filters[0].Apply(newUnstructured("v1", "ConfigMap", "prod", "api"))
+	g.Expect(err).To(gomega.MatchError("plugin is stopped"))
+}
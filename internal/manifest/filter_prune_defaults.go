@@ -0,0 +1,276 @@
+package manifest
+
+import (
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/k8s-manifest-tail/internal/fieldpath"
+	"github.com/grafana/k8s-manifest-tail/internal/openapi"
+)
+
+// pruneRule removes fields whose values the API server would have filled in had they been omitted.
+type pruneRule func(obj map[string]interface{})
+
+// fieldDefault is a value the API server's defaulting assigns to a field, addressed relative to the object that holds
+// it, such as a Pod spec.
+type fieldDefault struct {
+	path  string
+	value interface{}
+}
+
+// podSpecDefaults lists the defaults of Pod specs, wherever a workload embeds one.
+var podSpecDefaults = append([]fieldDefault{
+	{"restartPolicy", "Always"},
+	{"terminationGracePeriodSeconds", 30},
+	{"dnsPolicy", "ClusterFirst"},
+	{"schedulerName", "default-scheduler"},
+	{"securityContext", map[string]interface{}{}},
+	{"enableServiceLinks", true},
+	{"volumes[*].configMap.defaultMode", 420},
+	{"volumes[*].secret.defaultMode", 420},
+	{"volumes[*].projected.defaultMode", 420},
+	{"volumes[*].downwardAPI.defaultMode", 420},
+}, append(containerDefaults("containers"), containerDefaults("initContainers")...)...)
+
+func containerDefaults(field string) []fieldDefault {
+	defaults := []fieldDefault{
+		{field + "[*].terminationMessagePath", "/dev/termination-log"},
+		{field + "[*].terminationMessagePolicy", "File"},
+		{field + "[*].ports[*].protocol", "TCP"},
+		{field + "[*].resources", map[string]interface{}{}},
+	}
+	for _, probe := range []string{"livenessProbe", "readinessProbe", "startupProbe"} {
+		prefix := field + "[*]." + probe
+		defaults = append(defaults,
+			fieldDefault{prefix + ".timeoutSeconds", 1},
+			fieldDefault{prefix + ".periodSeconds", 10},
+			fieldDefault{prefix + ".successThreshold", 1},
+			fieldDefault{prefix + ".failureThreshold", 3},
+			fieldDefault{prefix + ".httpGet.scheme", "HTTP"},
+		)
+	}
+	return defaults
+}
+
+// jobSpecDefaults lists the defaults of Job specs, wherever a workload embeds one.
+var jobSpecDefaults = []fieldDefault{
+	{"backoffLimit", 6},
+	{"completionMode", "NonIndexed"},
+	{"suspend", false},
+}
+
+// kindDefaults lists the defaults of the fields of well-known kinds, outside of their Pod and Job specs.
+var kindDefaults = map[string][]fieldDefault{
+	"Deployment": {
+		{"spec.replicas", 1},
+		{"spec.revisionHistoryLimit", 10},
+		{"spec.progressDeadlineSeconds", 600},
+		{"spec.strategy", map[string]interface{}{
+			"type":          "RollingUpdate",
+			"rollingUpdate": map[string]interface{}{"maxUnavailable": "25%", "maxSurge": "25%"},
+		}},
+	},
+	"StatefulSet": {
+		{"spec.replicas", 1},
+		{"spec.revisionHistoryLimit", 10},
+		{"spec.podManagementPolicy", "OrderedReady"},
+		{"spec.updateStrategy", map[string]interface{}{
+			"type":          "RollingUpdate",
+			"rollingUpdate": map[string]interface{}{"partition": 0},
+		}},
+		{"spec.persistentVolumeClaimRetentionPolicy", map[string]interface{}{"whenDeleted": "Retain", "whenScaled": "Retain"}},
+	},
+	"DaemonSet": {
+		{"spec.revisionHistoryLimit", 10},
+		{"spec.updateStrategy", map[string]interface{}{
+			"type":          "RollingUpdate",
+			"rollingUpdate": map[string]interface{}{"maxUnavailable": 1, "maxSurge": 0},
+		}},
+	},
+	"ReplicaSet": {
+		{"spec.replicas", 1},
+	},
+	"CronJob": {
+		{"spec.concurrencyPolicy", "Allow"},
+		{"spec.suspend", false},
+		{"spec.successfulJobsHistoryLimit", 3},
+		{"spec.failedJobsHistoryLimit", 1},
+	},
+	"Service": {
+		{"spec.type", "ClusterIP"},
+		{"spec.sessionAffinity", "None"},
+		{"spec.internalTrafficPolicy", "Cluster"},
+		{"spec.ports[*].protocol", "TCP"},
+	},
+}
+
+// builtInGroups lists the API groups of the kinds in apiserverDefaults, so that custom resources that reuse a kind name
+// are left alone.
+var builtInGroups = []string{"", "apps", "batch"}
+
+// apiserverDefaults holds the rules for the defaults the API server assigns in code, which its OpenAPI documents do
+// not declare, keyed by kind.
+var apiserverDefaults = buildAPIServerDefaults()
+
+func buildAPIServerDefaults() map[string][]pruneRule {
+	rules := make(map[string][]pruneRule)
+	for kind, defaults := range kindDefaults {
+		rules[kind] = append(rules[kind], removeDefaults("", defaults))
+	}
+	for _, kind := range []string{"Pod", "Deployment", "ReplicaSet", "StatefulSet", "DaemonSet", "Job", "CronJob"} {
+		prefix := strings.Join(podSpecPath(kind), ".")
+		rules[kind] = append(rules[kind],
+			removeDefaults(prefix, podSpecDefaults),
+			removeImagePullPolicyDefaults(podSpecPath(kind)...),
+		)
+	}
+	for kind, prefix := range map[string][]string{"Job": {"spec"}, "CronJob": {"spec", "jobTemplate", "spec"}} {
+		rules[kind] = append(rules[kind],
+			removeDefaults(strings.Join(prefix, "."), jobSpecDefaults),
+			removeDefaultCompletions(prefix...),
+		)
+	}
+	rules["Service"] = append(rules["Service"], removeDefaultTargetPorts)
+	return rules
+}
+
+// podSpecPath returns the path of the Pod spec within objects of a well-known workload kind, or nil for other kinds.
+func podSpecPath(kind string) []string {
+	switch kind {
+	case "Pod":
+		return []string{"spec"}
+	case "Deployment", "ReplicaSet", "StatefulSet", "DaemonSet", "Job":
+		return []string{"spec", "template", "spec"}
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		return nil
+	}
+}
+
+// removeDefaults parses curated defaults below prefix, which are known to be valid.
+func removeDefaults(prefix string, defaults []fieldDefault) pruneRule {
+	type parsedDefault struct {
+		path  fieldpath.Path
+		value interface{}
+	}
+	parsed := make([]parsedDefault, 0, len(defaults))
+	for _, d := range defaults {
+		expression := d.path
+		if prefix != "" {
+			expression = prefix + "." + d.path
+		}
+		path, err := fieldpath.Parse(expression)
+		if err != nil {
+			panic(err)
+		}
+		parsed = append(parsed, parsedDefault{path: path, value: d.value})
+	}
+	return func(obj map[string]interface{}) {
+		for _, d := range parsed {
+			d.path.RemoveMatching(obj, func(value any) bool { return openapi.EqualJSON(value, d.value) })
+		}
+	}
+}
+
+// removeImagePullPolicyDefaults removes image pull policies equal to the default for their image: Always for images
+// tagged latest or not tagged at all, IfNotPresent otherwise.
+func removeImagePullPolicyDefaults(specPath ...string) pruneRule {
+	return func(obj map[string]interface{}) {
+		for _, field := range []string{"containers", "initContainers"} {
+			containers, _, _ := unstructured.NestedSlice(obj, append(specPath, field)...)
+			for _, raw := range containers {
+				container, ok := raw.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				image, _ := container["image"].(string)
+				if policy, ok := container["imagePullPolicy"].(string); ok && policy == defaultImagePullPolicy(image) {
+					delete(container, "imagePullPolicy")
+				}
+			}
+			if containers != nil {
+				_ = unstructured.SetNestedSlice(obj, containers, append(specPath, field)...)
+			}
+		}
+	}
+}
+
+func defaultImagePullPolicy(image string) string {
+	if strings.Contains(image, "@") {
+		return "IfNotPresent"
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if _, tag, tagged := strings.Cut(name, ":"); tagged && tag != "latest" {
+		return "IfNotPresent"
+	}
+	return "Always"
+}
+
+// removeDefaultCompletions removes completions and parallelism when both are 1, which is what the API server assigns
+// when both are omitted. Either one alone changes how the other is defaulted, so it is kept.
+func removeDefaultCompletions(specPath ...string) pruneRule {
+	return func(obj map[string]interface{}) {
+		completions, _, _ := unstructured.NestedFieldNoCopy(obj, append(specPath, "completions")...)
+		parallelism, _, _ := unstructured.NestedFieldNoCopy(obj, append(specPath, "parallelism")...)
+		if openapi.EqualJSON(completions, 1) && openapi.EqualJSON(parallelism, 1) {
+			unstructured.RemoveNestedField(obj, append(specPath, "completions")...)
+			unstructured.RemoveNestedField(obj, append(specPath, "parallelism")...)
+		}
+	}
+}
+
+// removeDefaultTargetPorts removes Service target ports equal to their port, which is what the API server assigns
+// when the target port is omitted.
+func removeDefaultTargetPorts(obj map[string]interface{}) {
+	ports, found, _ := unstructured.NestedSlice(obj, "spec", "ports")
+	if !found {
+		return
+	}
+	for _, raw := range ports {
+		port, ok := raw.(map[string]interface{})
+		if ok && port["targetPort"] != nil && openapi.EqualJSON(port["targetPort"], port["port"]) {
+			delete(port, "targetPort")
+		}
+	}
+	_ = unstructured.SetNestedSlice(obj, ports, "spec", "ports")
+}
+
+// PruneDefaultsFilter removes fields whose values equal their defaults: the defaults declared by the cluster's OpenAPI
+// schemas, which cover custom resources, and the defaults the API server assigns to built-in kinds in code. A field is
+// removed only when its value equals the default, so the manifest still describes the same object.
+type PruneDefaultsFilter struct {
+	// Source serves the OpenAPI documents. Without it, only the API server defaults are pruned.
+	Source *openapi.Source
+}
+
+// NewPruneDefaultsFilter builds a filter reading OpenAPI documents from the client of env, which may be nil, and caching
+// them in cacheDir. Documents that cannot be fetched are logged to the logger of env.
+func NewPruneDefaultsFilter(env FilterEnvironment, cacheDir string) PruneDefaultsFilter {
+	return PruneDefaultsFilter{Source: openapi.NewSource(env.OpenAPI, cacheDir, env.Logger)}
+}
+
+// Apply prunes the schema defaults of the object's kind, then the API server defaults. Objects whose schema is
+// unavailable are pruned of the API server defaults only.
+func (f PruneDefaultsFilter) Apply(obj *unstructured.Unstructured) error {
+	if obj == nil {
+		return nil
+	}
+	if f.Source != nil {
+		gvk := obj.GroupVersionKind()
+		if document, err := f.Source.Document(gvk.GroupVersion()); err == nil && document != nil {
+			if schema := document.SchemaFor(gvk); schema != nil {
+				document.PruneDefaults(schema, obj.Object)
+			}
+		}
+	}
+	if !slices.Contains(builtInGroups, obj.GroupVersionKind().Group) {
+		return nil
+	}
+	for _, rule := range apiserverDefaults[obj.GetKind()] {
+		rule(obj.Object)
+	}
+	return nil
+}
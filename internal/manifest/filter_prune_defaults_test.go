@@ -0,0 +1,268 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/client-go/openapi/openapitest"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+func TestPruneDefaultsFilter_APIServerDefaults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		manifest string
+		expected string
+	}{
+		{
+			name: "deployment",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api}
+spec:
+  replicas: 1
+  revisionHistoryLimit: 5
+  progressDeadlineSeconds: 600
+  strategy: {type: RollingUpdate, rollingUpdate: {maxUnavailable: 25%, maxSurge: 25%}}
+  template:
+    spec:
+      restartPolicy: Always
+      terminationGracePeriodSeconds: 30
+      dnsPolicy: ClusterFirst
+      schedulerName: default-scheduler
+      securityContext: {}
+      containers:
+        - name: app
+          image: example/app:1.2
+          imagePullPolicy: IfNotPresent
+          terminationMessagePath: /dev/termination-log
+          terminationMessagePolicy: File
+          resources: {}
+          ports: [{containerPort: 8080, protocol: TCP}, {containerPort: 8125, protocol: UDP}]
+          readinessProbe: {httpGet: {path: /ready, port: 8080, scheme: HTTP}, timeoutSeconds: 1, periodSeconds: 5, successThreshold: 1, failureThreshold: 3}
+        - name: sidecar
+          image: example/sidecar
+          imagePullPolicy: IfNotPresent
+      volumes:
+        - {name: config, configMap: {name: api, defaultMode: 420}}
+        - {name: token, secret: {secretName: api, defaultMode: 256}}
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api}
+spec:
+  revisionHistoryLimit: 5
+  template:
+    spec:
+      containers:
+        - name: app
+          image: example/app:1.2
+          ports: [{containerPort: 8080}, {containerPort: 8125, protocol: UDP}]
+          readinessProbe: {httpGet: {path: /ready, port: 8080}, periodSeconds: 5}
+        - name: sidecar
+          image: example/sidecar
+          imagePullPolicy: IfNotPresent
+      volumes:
+        - {name: config, configMap: {name: api}}
+        - {name: token, secret: {secretName: api, defaultMode: 256}}
+`,
+		},
+		{
+			name: "cron job",
+			manifest: `
+apiVersion: batch/v1
+kind: CronJob
+metadata: {name: report}
+spec:
+  schedule: "0 * * * *"
+  concurrencyPolicy: Allow
+  suspend: false
+  successfulJobsHistoryLimit: 3
+  failedJobsHistoryLimit: 1
+  jobTemplate:
+    spec:
+      backoffLimit: 6
+      completions: 1
+      parallelism: 1
+      completionMode: NonIndexed
+      template:
+        spec:
+          restartPolicy: OnFailure
+          containers:
+            - {name: report, image: "example/report@sha256:0123", imagePullPolicy: IfNotPresent}
+`,
+			expected: `
+apiVersion: batch/v1
+kind: CronJob
+metadata: {name: report}
+spec:
+  schedule: "0 * * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          restartPolicy: OnFailure
+          containers:
+            - {name: report, image: "example/report@sha256:0123"}
+`,
+		},
+		{
+			name: "job with only parallelism",
+			manifest: `
+apiVersion: batch/v1
+kind: Job
+metadata: {name: queue}
+spec:
+  parallelism: 1
+  template: {spec: {restartPolicy: Never, containers: [{name: worker, image: "example/worker:latest", imagePullPolicy: Always}]}}
+`,
+			expected: `
+apiVersion: batch/v1
+kind: Job
+metadata: {name: queue}
+spec:
+  parallelism: 1
+  template: {spec: {restartPolicy: Never, containers: [{name: worker, image: "example/worker:latest"}]}}
+`,
+		},
+		{
+			name: "service",
+			manifest: `
+apiVersion: v1
+kind: Service
+metadata: {name: api}
+spec:
+  type: ClusterIP
+  sessionAffinity: None
+  internalTrafficPolicy: Cluster
+  ports: [{name: http, port: 80, targetPort: 80, protocol: TCP}, {name: metrics, port: 9090, targetPort: metrics}]
+`,
+			expected: `
+apiVersion: v1
+kind: Service
+metadata: {name: api}
+spec:
+  ports: [{name: http, port: 80}, {name: metrics, port: 9090, targetPort: metrics}]
+`,
+		},
+		{
+			name: "custom resource reusing a built-in kind",
+			manifest: `
+apiVersion: example.com/v1
+kind: Service
+metadata: {name: api}
+spec: {type: ClusterIP}
+`,
+			expected: `
+apiVersion: example.com/v1
+kind: Service
+metadata: {name: api}
+spec: {type: ClusterIP}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewWithT(t)
+
+			filter := NewPruneDefaultsFilter(FilterEnvironment{}, t.TempDir())
+			obj := decodeManifest(t, test.manifest)
+			g.Expect(filter.Apply(obj)).To(gomega.Succeed())
+			g.Expect(obj.Object).To(gomega.Equal(decodeManifest(t, test.expected).Object))
+		})
+	}
+}
+
+const widget = `
+apiVersion: example.com/v1
+kind: Widget
+metadata: {name: gadget, namespace: default}
+spec:
+  replicas: 1
+  mode: slow
+  options: {retries: 3}
+  ports: [{port: 80, protocol: TCP}, {port: 53, protocol: UDP}]
+  limits: {cpu: {burst: 0}, memory: {burst: 2}}
+`
+
+const prunedWidget = `
+apiVersion: example.com/v1
+kind: Widget
+metadata: {name: gadget, namespace: default}
+spec:
+  mode: slow
+  ports: [{port: 80}, {port: 53, protocol: UDP}]
+  limits: {cpu: {}, memory: {burst: 2}}
+`
+
+func TestPruneDefaultsFilter_SchemaDefaults(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter, err := NewFilter(config.FilterConfig{Name: config.FilterPruneDefaults, CacheDirectory: t.TempDir()},
+		FilterEnvironment{OpenAPI: openapitest.NewFileClient("testdata/openapi")})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	obj := decodeManifest(t, widget)
+	g.Expect(filter.Apply(obj)).To(gomega.Succeed())
+	g.Expect(obj.Object).To(gomega.Equal(decodeManifest(t, prunedWidget).Object))
+}
+
+func TestPruneDefaultsFilter_UsesCachedDocumentsOffline(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	cacheDir := t.TempDir()
+	online := NewPruneDefaultsFilter(FilterEnvironment{OpenAPI: openapitest.NewFileClient("testdata/openapi")}, cacheDir)
+	g.Expect(online.Apply(decodeManifest(t, widget))).To(gomega.Succeed())
+
+	offline := NewPruneDefaultsFilter(FilterEnvironment{}, cacheDir)
+	obj := decodeManifest(t, widget)
+	g.Expect(offline.Apply(obj)).To(gomega.Succeed())
+	g.Expect(obj.Object).To(gomega.Equal(decodeManifest(t, prunedWidget).Object))
+
+	unknown := NewPruneDefaultsFilter(FilterEnvironment{}, t.TempDir())
+	obj = decodeManifest(t, widget)
+	g.Expect(unknown.Apply(obj)).To(gomega.Succeed())
+	g.Expect(obj.Object).To(gomega.Equal(decodeManifest(t, widget).Object))
+}
+
+func TestPruneDefaultsFilter_BuiltInSchemas(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter := NewPruneDefaultsFilter(FilterEnvironment{OpenAPI: openapitest.NewEmbeddedFileClient()}, t.TempDir())
+	obj := decodeManifest(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api, labels: {app: api}}
+spec:
+  replicas: 3
+  selector: {matchLabels: {app: api}}
+  template:
+    metadata: {labels: {app: api}}
+    spec:
+      dnsPolicy: ClusterFirst
+      containers: [{name: app, image: "example/app:1.2", imagePullPolicy: IfNotPresent, env: [{name: MODE, value: fast}]}]
+`)
+	g.Expect(filter.Apply(obj)).To(gomega.Succeed())
+	g.Expect(obj.Object).To(gomega.Equal(decodeManifest(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api, labels: {app: api}}
+spec:
+  replicas: 3
+  selector: {matchLabels: {app: api}}
+  template:
+    metadata: {labels: {app: api}}
+    spec:
+      containers: [{name: app, image: "example/app:1.2", env: [{name: MODE, value: fast}]}]
+`).Object))
+}
@@ -0,0 +1,119 @@
+package manifest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// lastAppliedAnnotation holds the manifest last applied with kubectl apply, which repeats the data being redacted.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// RedactDataFilter replaces the values of Secret data and stringData, and of ConfigMap data and binaryData entries
+// whose keys match KeyPatterns, keeping the keys.
+type RedactDataFilter struct {
+	Redactor    Redactor
+	KeyPatterns []*regexp.Regexp
+}
+
+// NewRedactDataFilter compiles the ConfigMap key patterns into a filter.
+func NewRedactDataFilter(redactor Redactor, keyPatterns []string) (RedactDataFilter, error) {
+	filter := RedactDataFilter{Redactor: redactor}
+	for _, pattern := range keyPatterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return RedactDataFilter{}, fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+		}
+		filter.KeyPatterns = append(filter.KeyPatterns, compiled)
+	}
+	return filter, nil
+}
+
+// Apply redacts the data of core Secrets and ConfigMaps, including the copy kept in the last-applied-configuration
+// annotation. The annotation is removed when it cannot be parsed, since it may hold the original values.
+func (f RedactDataFilter) Apply(obj *unstructured.Unstructured) error {
+	if obj == nil || obj.GetAPIVersion() != "v1" || !f.redacts(obj.GetKind()) {
+		return nil
+	}
+	f.redact(obj.GetKind(), obj.Object)
+	return redactLastApplied(obj, func(applied map[string]interface{}) bool {
+		f.redact(obj.GetKind(), applied)
+		return true
+	})
+}
+
+// redactLastApplied runs redact on the manifest kept in the last-applied-configuration annotation, and stores it again
+// when redact reports a change. The annotation is removed when it cannot be parsed, since it may hold the original
+// values.
+func redactLastApplied(obj *unstructured.Unstructured, redact func(applied map[string]interface{}) bool) error {
+	annotations := obj.GetAnnotations()
+	lastApplied, ok := annotations[lastAppliedAnnotation]
+	if !ok {
+		return nil
+	}
+	var applied map[string]interface{}
+	if err := json.Unmarshal([]byte(lastApplied), &applied); err != nil {
+		delete(annotations, lastAppliedAnnotation)
+		obj.SetAnnotations(annotations)
+		return nil
+	}
+	if !redact(applied) {
+		return nil
+	}
+	redacted, err := json.Marshal(applied)
+	if err != nil {
+		return fmt.Errorf("encode %s annotation: %w", lastAppliedAnnotation, err)
+	}
+	annotations[lastAppliedAnnotation] = string(redacted)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+func (f RedactDataFilter) redacts(kind string) bool {
+	return kind == "Secret" || (kind == "ConfigMap" && len(f.KeyPatterns) > 0)
+}
+
+func (f RedactDataFilter) redact(kind string, obj map[string]interface{}) {
+	switch kind {
+	case "Secret":
+		f.redactValues(obj, "data", true, nil)
+		f.redactValues(obj, "stringData", false, nil)
+	case "ConfigMap":
+		f.redactValues(obj, "data", false, f.matchesKey)
+		f.redactValues(obj, "binaryData", true, f.matchesKey)
+	}
+}
+
+// redactValues redacts the entries of the field that match, or every entry when match is nil. Base64-encoded values
+// are decoded first so that a value has the same fingerprint in data and stringData.
+func (f RedactDataFilter) redactValues(obj map[string]interface{}, field string, encoded bool, match func(string) bool) {
+	values, ok := obj[field].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key, value := range values {
+		text, ok := value.(string)
+		if !ok || (match != nil && !match(key)) {
+			continue
+		}
+		raw := []byte(text)
+		if encoded {
+			if decoded, err := base64.StdEncoding.DecodeString(text); err == nil {
+				raw = decoded
+			}
+		}
+		values[key] = f.Redactor.Redact(raw)
+	}
+}
+
+func (f RedactDataFilter) matchesKey(key string) bool {
+	for _, pattern := range f.KeyPatterns {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
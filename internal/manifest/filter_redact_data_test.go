@@ -0,0 +1,104 @@
+package manifest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+func TestRedactDataFilterRedactsSecrets(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	secret := newUnstructured("v1", "Secret", "default", "db")
+	secret.Object["data"] = map[string]interface{}{"password": base64.StdEncoding.EncodeToString([]byte("hunter2"))}
+	secret.Object["stringData"] = map[string]interface{}{"username": "admin"}
+	secret.SetAnnotations(map[string]string{
+		lastAppliedAnnotation: `{"apiVersion":"v1","kind":"Secret","stringData":{"username":"admin"}}`,
+		"team":                "edge",
+	})
+
+	filter, err := NewRedactDataFilter(Redactor{}, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(filter.Apply(secret)).To(gomega.Succeed())
+
+	g.Expect(secret.Object["data"]).To(gomega.Equal(map[string]interface{}{"password": config.DefaultRedactedValue}))
+	g.Expect(secret.Object["stringData"]).To(gomega.Equal(map[string]interface{}{"username": config.DefaultRedactedValue}))
+	g.Expect(secret.GetAnnotations()).To(gomega.HaveKeyWithValue("team", "edge"))
+
+	var applied map[string]interface{}
+	g.Expect(json.Unmarshal([]byte(secret.GetAnnotations()[lastAppliedAnnotation]), &applied)).To(gomega.Succeed())
+	g.Expect(applied["stringData"]).To(gomega.Equal(map[string]interface{}{"username": config.DefaultRedactedValue}))
+}
+
+func TestRedactDataFilterDropsUnparsableLastAppliedConfiguration(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	secret := newUnstructured("v1", "Secret", "default", "db")
+	secret.SetAnnotations(map[string]string{lastAppliedAnnotation: "password: hunter2"})
+
+	g.Expect(RedactDataFilter{}.Apply(secret)).To(gomega.Succeed())
+	g.Expect(secret.GetAnnotations()).NotTo(gomega.HaveKey(lastAppliedAnnotation))
+}
+
+func TestRedactDataFilterRedactsMatchingConfigMapKeys(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	configMap := newUnstructured("v1", "ConfigMap", "default", "settings")
+	configMap.Object["data"] = map[string]interface{}{"log-level": "debug", "db-password": "hunter2"}
+	configMap.Object["binaryData"] = map[string]interface{}{"tls.key": "AAEC"}
+
+	filter, err := NewRedactDataFilter(Redactor{placeholder: "***"}, []string{"password", `\.key$`})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(filter.Apply(configMap)).To(gomega.Succeed())
+
+	g.Expect(configMap.Object["data"]).To(gomega.Equal(map[string]interface{}{"log-level": "debug", "db-password": "***"}))
+	g.Expect(configMap.Object["binaryData"]).To(gomega.Equal(map[string]interface{}{"tls.key": "***"}))
+
+	untouched := newUnstructured("v1", "ConfigMap", "default", "settings")
+	untouched.Object["data"] = map[string]interface{}{"db-password": "hunter2"}
+	g.Expect(RedactDataFilter{}.Apply(untouched)).To(gomega.Succeed())
+	g.Expect(untouched.Object["data"]).To(gomega.Equal(map[string]interface{}{"db-password": "hunter2"}))
+
+	_, err = NewRedactDataFilter(Redactor{}, []string{"("})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestRedactDataFilterIgnoresOtherGroups(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	external := newUnstructured("external-secrets.io/v1", "Secret", "default", "db")
+	external.Object["data"] = map[string]interface{}{"password": "hunter2"}
+
+	g.Expect(RedactDataFilter{}.Apply(external)).To(gomega.Succeed())
+	g.Expect(external.Object["data"]).To(gomega.Equal(map[string]interface{}{"password": "hunter2"}))
+}
+
+func TestRedactDataFilterHashesValues(t *testing.T) {
+	t.Setenv("REDACTION_KEY", "salt")
+	g := gomega.NewWithT(t)
+
+	filter, err := NewFilter(config.FilterConfig{Name: config.FilterRedactData, Hash: &config.HashConfig{KeyEnv: "REDACTION_KEY"}}, FilterEnvironment{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	secret := newUnstructured("v1", "Secret", "default", "db")
+	secret.Object["data"] = map[string]interface{}{"password": base64.StdEncoding.EncodeToString([]byte("hunter2"))}
+	secret.Object["stringData"] = map[string]interface{}{"password": "hunter2", "other": "hunter3"}
+	g.Expect(filter.Apply(secret)).To(gomega.Succeed())
+
+	hashed := secret.Object["data"].(map[string]interface{})["password"]
+	g.Expect(hashed).To(gomega.MatchRegexp(`^<Redacted sha256:[0-9a-f]{16}>$`))
+	g.Expect(secret.Object["stringData"]).To(gomega.HaveKeyWithValue("password", hashed))
+	g.Expect(secret.Object["stringData"]).NotTo(gomega.HaveKeyWithValue("other", hashed))
+
+	t.Setenv("REDACTION_KEY", "")
+	_, err = NewFilter(config.FilterConfig{Name: config.FilterRedactData, Hash: &config.HashConfig{KeyEnv: "REDACTION_KEY"}}, FilterEnvironment{})
+	g.Expect(err).To(gomega.MatchError("environment variable REDACTION_KEY for the hash key is not set"))
+}
@@ -1,94 +1,139 @@
 package manifest
 
-import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+import (
+	"fmt"
+	"slices"
 
-// RedactEnvValuesFilter masks literal environment variable values inside Pod specs.
-type RedactEnvValuesFilter struct{}
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
-var redactedValue = "<Redacted>"
+	"github.com/grafana/k8s-manifest-tail/internal/fieldpath"
+)
 
-// Apply redacts env[].value fields in Pod specs and well-known workload templates.
-func (RedactEnvValuesFilter) Apply(obj *unstructured.Unstructured) error {
+// RedactEnvValuesFilter masks literal environment variable values inside Pod specs, wherever an object embeds one, and
+// inside the containers listed by ContainerPaths.
+type RedactEnvValuesFilter struct {
+	Redactor Redactor
+	// ContainerPaths selects more containers, or lists of containers, keyed by kind or "*" for every kind. It covers
+	// custom resources whose containers do not sit in a Pod spec, such as the steps of a Tekton Task.
+	ContainerPaths map[string][]fieldpath.Path
+}
+
+// podSpecContainerFields lists the fields of a Pod spec that hold containers.
+var podSpecContainerFields = []string{"containers", "initContainers", "ephemeralContainers"}
+
+// NewRedactEnvValuesFilter builds a filter that also redacts the containers selected by containerPaths, keyed by kind
+// or "*".
+func NewRedactEnvValuesFilter(redactor Redactor, containerPaths map[string][]string) (RedactEnvValuesFilter, error) {
+	filter := RedactEnvValuesFilter{Redactor: redactor, ContainerPaths: make(map[string][]fieldpath.Path, len(containerPaths))}
+	for kind, expressions := range containerPaths {
+		paths, err := parsePaths(expressions)
+		if err != nil {
+			return RedactEnvValuesFilter{}, fmt.Errorf("container paths for %s: %w", kind, err)
+		}
+		filter.ContainerPaths[kind] = paths
+	}
+	return filter, nil
+}
+
+// Apply redacts env[].value fields in every Pod spec of the object, found by its shape rather than by the object's kind,
+// and in the configured containers. The copy of the object kept in the last-applied-configuration annotation is
+// redacted the same way.
+func (f RedactEnvValuesFilter) Apply(obj *unstructured.Unstructured) error {
 	if obj == nil {
 		return nil
 	}
+	f.redact(obj.GetKind(), obj.Object)
+	return redactLastApplied(obj, func(applied map[string]interface{}) bool {
+		return f.redact(obj.GetKind(), applied) > 0
+	})
+}
 
-	var specPath []string
-	switch obj.GetKind() {
-	case "Pod":
-		specPath = []string{"spec"}
-	case "Deployment", "ReplicaSet", "StatefulSet", "DaemonSet":
-		specPath = []string{"spec", "template", "spec"}
-	case "Job":
-		specPath = []string{"spec", "template", "spec"}
-	case "CronJob":
-		specPath = []string{"spec", "jobTemplate", "spec", "template", "spec"}
-	default:
-		return nil
+// redact redacts the env values of the containers in an object of the given kind, and returns how many it redacted.
+func (f RedactEnvValuesFilter) redact(kind string, obj map[string]interface{}) int {
+	var containers []interface{}
+	for _, spec := range findPodSpecs(obj) {
+		for _, field := range podSpecContainerFields {
+			if list, ok := spec[field].([]interface{}); ok {
+				containers = append(containers, list...)
+			}
+		}
+	}
+	for _, path := range slices.Concat(f.ContainerPaths[anyKind], f.ContainerPaths[kind]) {
+		for _, selected := range path.Find(obj) {
+			if list, ok := selected.([]interface{}); ok {
+				containers = append(containers, list...)
+			} else {
+				containers = append(containers, selected)
+			}
+		}
 	}
 
-	return redactEnvAt(obj.Object, specPath...)
+	// A container can be both part of a Pod spec and selected by a path; redact it once, so that hashes are not hashed
+	// again.
+	redacted := make(map[string]bool)
+	count := 0
+	for _, raw := range containers {
+		container, ok := raw.(map[string]interface{})
+		if !ok || redacted[fmt.Sprintf("%p", container)] {
+			continue
+		}
+		redacted[fmt.Sprintf("%p", container)] = true
+		count += redactContainerEnv(container, f.Redactor)
+	}
+	return count
 }
 
-func redactEnvAt(obj map[string]interface{}, path ...string) error {
-	spec, found, err := unstructured.NestedMap(obj, path...)
-	if err != nil || !found {
-		return err
+// findPodSpecs returns every map in value that looks like a Pod spec: one with a list of container objects. This
+// finds the Pod templates of custom workloads, such as Argo Rollouts, Knative Services, and KEDA ScaledJobs.
+func findPodSpecs(value interface{}) []map[string]interface{} {
+	var specs []map[string]interface{}
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		if isPodSpec(typed) {
+			specs = append(specs, typed)
+		}
+		for _, child := range typed {
+			specs = append(specs, findPodSpecs(child)...)
+		}
+	case []interface{}:
+		for _, child := range typed {
+			specs = append(specs, findPodSpecs(child)...)
+		}
 	}
+	return specs
+}
 
-	anyChanged := false
-	for _, field := range []string{"containers", "initContainers"} {
-		raw, ok := spec[field]
-		if !ok || raw == nil {
-			continue
+func isPodSpec(obj map[string]interface{}) bool {
+	containers, ok := obj["containers"].([]interface{})
+	if !ok || len(containers) == 0 {
+		return false
+	}
+	for _, container := range containers {
+		if _, ok := container.(map[string]interface{}); !ok {
+			return false
 		}
-		containerSlice, ok := raw.([]interface{})
+	}
+	return true
+}
+
+func redactContainerEnv(container map[string]interface{}, redactor Redactor) int {
+	envSlice, ok := container["env"].([]interface{})
+	if !ok {
+		return 0
+	}
+	count := 0
+	for _, env := range envSlice {
+		envMap, ok := env.(map[string]interface{})
 		if !ok {
 			continue
 		}
-		changed := false
-		for i, c := range containerSlice {
-			containerMap, ok := c.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			envRaw, exists := containerMap["env"]
-			if !exists || envRaw == nil {
-				continue
-			}
-			envSlice, ok := envRaw.([]interface{})
-			if !ok {
-				continue
-			}
-			envChanged := false
-			for j, env := range envSlice {
-				envMap, ok := env.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				if _, hasValueFrom := envMap["valueFrom"]; hasValueFrom {
-					continue
-				}
-				if _, ok := envMap["value"]; ok {
-					envMap["value"] = redactedValue
-					envSlice[j] = envMap
-					envChanged = true
-				}
-			}
-			if envChanged {
-				containerMap["env"] = envSlice
-				containerSlice[i] = containerMap
-				changed = true
-			}
+		if _, hasValueFrom := envMap["valueFrom"]; hasValueFrom {
+			continue
 		}
-		if changed {
-			spec[field] = containerSlice
-			anyChanged = true
+		if value, ok := envMap["value"]; ok {
+			envMap["value"] = redactor.Redact([]byte(fmt.Sprint(value)))
+			count++
 		}
 	}
-
-	if anyChanged {
-		return unstructured.SetNestedMap(obj, spec, path...)
-	}
-	return nil
+	return count
 }
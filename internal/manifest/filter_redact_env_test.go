@@ -1,9 +1,15 @@
 package manifest
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
 )
 
 func TestRedactEnvValuesFilter(t *testing.T) {
@@ -30,10 +36,38 @@ func TestRedactEnvValuesFilter(t *testing.T) {
 	env := pod.Object["spec"].(map[string]interface{})["containers"].([]interface{})[0].(map[string]interface{})["env"].([]interface{})
 	first := env[0].(map[string]interface{})
 	second := env[1].(map[string]interface{})
-	g.Expect(first["value"]).To(gomega.Equal(redactedValue))
+	g.Expect(first["value"]).To(gomega.Equal(config.DefaultRedactedValue))
 	g.Expect(second).To(gomega.HaveKey("valueFrom"))
 }
 
+func TestRedactEnvValuesFilterRedactsLastAppliedConfiguration(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	lastApplied := `{"apiVersion":"apps/v1","kind":"Deployment","spec":{"template":{"spec":{"containers":[{"name":"app","env":[{"name":"TOKEN","value":"abc"}]}]}}}}` + "\n"
+	deployment := newUnstructured("apps/v1", "Deployment", "default", "api")
+	deployment.SetAnnotations(map[string]string{lastAppliedAnnotation: lastApplied})
+
+	g.Expect(RedactEnvValuesFilter{}.Apply(deployment)).To(gomega.Succeed())
+
+	var applied map[string]interface{}
+	g.Expect(json.Unmarshal([]byte(deployment.GetAnnotations()[lastAppliedAnnotation]), &applied)).To(gomega.Succeed())
+	containers, _, _ := unstructured.NestedSlice(applied, "spec", "template", "spec", "containers")
+	g.Expect(containers[0]).To(gomega.HaveKeyWithValue("env", []interface{}{map[string]interface{}{"name": "TOKEN", "value": config.DefaultRedactedValue}}))
+
+	// An annotation without env values is left as written.
+	service := newUnstructured("v1", "Service", "default", "api")
+	service.SetAnnotations(map[string]string{lastAppliedAnnotation: `{"kind":"Service"}` + "\n"})
+	g.Expect(RedactEnvValuesFilter{}.Apply(service)).To(gomega.Succeed())
+	g.Expect(service.GetAnnotations()).To(gomega.HaveKeyWithValue(lastAppliedAnnotation, `{"kind":"Service"}`+"\n"))
+
+	// An annotation that cannot be parsed may hold the original values, so it is removed.
+	pod := newUnstructured("v1", "Pod", "default", "api")
+	pod.SetAnnotations(map[string]string{lastAppliedAnnotation: "env: TOKEN=abc"})
+	g.Expect(RedactEnvValuesFilter{}.Apply(pod)).To(gomega.Succeed())
+	g.Expect(pod.GetAnnotations()).NotTo(gomega.HaveKey(lastAppliedAnnotation))
+}
+
 func TestRedactEnvValuesFilterHandlesCronJob(t *testing.T) {
 	t.Parallel()
 	g := gomega.NewWithT(t)
@@ -63,5 +97,184 @@ func TestRedactEnvValuesFilterHandlesCronJob(t *testing.T) {
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 
 	env := cronJob.Object["spec"].(map[string]interface{})["jobTemplate"].(map[string]interface{})["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})[0].(map[string]interface{})["env"].([]interface{})
-	g.Expect(env[0].(map[string]interface{})["value"]).To(gomega.Equal(redactedValue))
+	g.Expect(env[0].(map[string]interface{})["value"]).To(gomega.Equal(config.DefaultRedactedValue))
+}
+
+func TestRedactEnvValuesFilterUsesReplacement(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	pod := newUnstructured("v1", "Pod", "default", "api")
+	pod.Object["spec"] = map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name": "app",
+				"env":  []interface{}{map[string]interface{}{"name": "FOO", "value": "bar"}},
+			},
+		},
+	}
+
+	err := RedactEnvValuesFilter{Redactor: Redactor{placeholder: "***"}}.Apply(pod)
+
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	env := pod.Object["spec"].(map[string]interface{})["containers"].([]interface{})[0].(map[string]interface{})["env"].([]interface{})
+	g.Expect(env[0].(map[string]interface{})["value"]).To(gomega.Equal("***"))
+}
+
+func TestHashedEnvValuesKeepChangesVisible(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	keyFile := filepath.Join(t.TempDir(), "key")
+	g.Expect(os.WriteFile(keyFile, []byte("salt\n"), 0o600)).To(gomega.Succeed())
+	cfg := &config.Config{Filters: []config.FilterConfig{
+		{Name: config.FilterRedactEnvValues, Hash: &config.HashConfig{KeyFile: keyFile}},
+	}}
+	processor := NewRuleFilters(NewWriter(config.OutputConfig{Directory: t.TempDir(), Format: config.OutputFormatYAML}), FilterEnvironment{})
+	rule := config.ObjectRule{Kind: "Pod"}
+
+	podWithToken := func(token string) *unstructured.Unstructured {
+		pod := newUnstructured("v1", "Pod", "default", "api")
+		pod.Object["spec"] = map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name": "app",
+					"env":  []interface{}{map[string]interface{}{"name": "TOKEN", "value": token}},
+				},
+			},
+		}
+		return pod
+	}
+	envValue := func(obj *unstructured.Unstructured) interface{} {
+		containers := obj.Object["spec"].(map[string]interface{})["containers"].([]interface{})
+		return containers[0].(map[string]interface{})["env"].([]interface{})[0].(map[string]interface{})["value"]
+	}
+
+	diff, err := processor.Process(rule, podWithToken("first"), cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(envValue(diff.Current)).To(gomega.MatchRegexp(`^<Redacted sha256:[0-9a-f]{16}>$`))
+
+	diff, err = processor.Process(rule, podWithToken("first"), cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(diff).To(gomega.BeNil())
+
+	diff, err = processor.Process(rule, podWithToken("second"), cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(diff).NotTo(gomega.BeNil())
+	g.Expect(envValue(diff.Current)).NotTo(gomega.Equal(envValue(diff.Previous)))
+	g.Expect(envValue(diff.Current)).NotTo(gomega.ContainSubstring("second"))
+}
+
+func TestRedactEnvValuesFilterFindsPodSpecsInCustomResources(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter, err := NewFilter(config.FilterConfig{Name: config.FilterRedactEnvValues}, FilterEnvironment{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	for _, test := range []struct{ manifest, expected string }{
+		{
+			manifest: `
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata: {name: api}
+spec:
+  template:
+    spec:
+      containers: [{name: app, env: [{name: TOKEN, value: shhh}]}]
+      initContainers: [{name: init, env: [{name: MODE, value: setup}]}]
+`,
+			expected: `
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata: {name: api}
+spec:
+  template:
+    spec:
+      containers: [{name: app, env: [{name: TOKEN, value: <Redacted>}]}]
+      initContainers: [{name: init, env: [{name: MODE, value: <Redacted>}]}]
+`,
+		},
+		{
+			manifest: `
+apiVersion: keda.sh/v1alpha1
+kind: ScaledJob
+metadata: {name: worker}
+spec:
+  jobTargetRef:
+    template:
+      spec:
+        containers: [{name: worker, env: [{name: PASSWORD, value: hunter2}, {name: KEY, valueFrom: {secretKeyRef: {name: worker, key: key}}}]}]
+`,
+			expected: `
+apiVersion: keda.sh/v1alpha1
+kind: ScaledJob
+metadata: {name: worker}
+spec:
+  jobTargetRef:
+    template:
+      spec:
+        containers: [{name: worker, env: [{name: PASSWORD, value: <Redacted>}, {name: KEY, valueFrom: {secretKeyRef: {name: worker, key: key}}}]}]
+`,
+		},
+		{
+			manifest: `
+apiVersion: v1
+kind: Pod
+metadata: {name: api}
+spec:
+  containers: [{name: app}]
+  ephemeralContainers: [{name: debugger, env: [{name: TOKEN, value: shhh}]}]
+`,
+			expected: `
+apiVersion: v1
+kind: Pod
+metadata: {name: api}
+spec:
+  containers: [{name: app}]
+  ephemeralContainers: [{name: debugger, env: [{name: TOKEN, value: <Redacted>}]}]
+`,
+		},
+	} {
+		obj := decodeManifest(t, test.manifest)
+		g.Expect(filter.Apply(obj)).To(gomega.Succeed())
+		g.Expect(obj.Object).To(gomega.Equal(decodeManifest(t, test.expected).Object))
+	}
+}
+
+func TestRedactEnvValuesFilterRedactsConfiguredContainers(t *testing.T) {
+	t.Setenv("REDACTION_KEY", "salt")
+	g := gomega.NewWithT(t)
+
+	filter, err := NewFilter(config.FilterConfig{
+		Name: config.FilterRedactEnvValues,
+		Hash: &config.HashConfig{KeyEnv: "REDACTION_KEY"},
+		ContainerPaths: map[string][]string{
+			"Task": {"spec.steps", "spec.sidecars[*]"},
+			"*":    {"spec.containers"},
+		},
+	}, FilterEnvironment{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	obj := decodeManifest(t, `
+apiVersion: tekton.dev/v1
+kind: Task
+metadata: {name: build}
+spec:
+  steps: [{name: build, env: [{name: TOKEN, value: shhh}]}]
+  sidecars: [{name: registry, env: [{name: PASSWORD, value: hunter2}]}]
+  containers: [{name: app, env: [{name: MODE, value: fast}]}]
+`)
+	g.Expect(filter.Apply(obj)).To(gomega.Succeed())
+
+	redactor, err := NewRedactor(config.FilterConfig{Hash: &config.HashConfig{KeyEnv: "REDACTION_KEY"}})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	spec := obj.Object["spec"].(map[string]interface{})
+	envValue := func(field string) interface{} {
+		container := spec[field].([]interface{})[0].(map[string]interface{})
+		return container["env"].([]interface{})[0].(map[string]interface{})["value"]
+	}
+	g.Expect(envValue("steps")).To(gomega.Equal(redactor.Redact([]byte("shhh"))))
+	g.Expect(envValue("sidecars")).To(gomega.Equal(redactor.Redact([]byte("hunter2"))))
+	// Selected by a path and found as a Pod spec, but hashed only once.
+	g.Expect(envValue("containers")).To(gomega.Equal(redactor.Redact([]byte("fast"))))
 }
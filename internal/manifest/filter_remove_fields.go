@@ -0,0 +1,32 @@
+package manifest
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/k8s-manifest-tail/internal/fieldpath"
+)
+
+// RemoveFieldsFilter deletes the fields selected by JSONPath expressions or JSON Pointers.
+type RemoveFieldsFilter struct {
+	Paths []fieldpath.Path
+}
+
+// NewRemoveFieldsFilter parses the supplied field paths into a filter.
+func NewRemoveFieldsFilter(expressions []string) (RemoveFieldsFilter, error) {
+	paths, err := parsePaths(expressions)
+	if err != nil {
+		return RemoveFieldsFilter{}, err
+	}
+	return RemoveFieldsFilter{Paths: paths}, nil
+}
+
+// Apply removes every field selected by the filter's paths. Paths that select nothing are ignored.
+func (f RemoveFieldsFilter) Apply(obj *unstructured.Unstructured) error {
+	if obj == nil {
+		return nil
+	}
+	for _, path := range f.Paths {
+		path.Remove(obj.Object)
+	}
+	return nil
+}
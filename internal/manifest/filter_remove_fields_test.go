@@ -0,0 +1,67 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+func TestRemoveFieldsFilter(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	deployment := newUnstructured("apps/v1", "Deployment", "default", "api")
+	deployment.SetAnnotations(map[string]string{"deployment.kubernetes.io/revision": "3", "team": "edge"})
+	deployment.Object["spec"] = map[string]interface{}{
+		"template": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "app", "terminationMessagePath": "/dev/termination-log"},
+					map[string]interface{}{"name": "sidecar", "terminationMessagePath": "/dev/termination-log"},
+				},
+			},
+		},
+	}
+
+	filter, err := NewRemoveFieldsFilter([]string{
+		`metadata.annotations["deployment.kubernetes.io/revision"]`,
+		`spec.template.spec.containers[*].terminationMessagePath`,
+		`status.conditions`,
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(filter.Apply(deployment)).To(gomega.Succeed())
+
+	g.Expect(deployment.GetAnnotations()).To(gomega.Equal(map[string]string{"team": "edge"}))
+	containers := deployment.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+	g.Expect(containers).To(gomega.Equal([]interface{}{
+		map[string]interface{}{"name": "app"},
+		map[string]interface{}{"name": "sidecar"},
+	}))
+
+	_, err = NewRemoveFieldsFilter([]string{"spec.containers[x]"})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestFilterLimitedToKinds(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter, err := NewFilter(config.FilterConfig{
+		Name:  config.FilterRemoveFields,
+		Kinds: []string{"Deployment"},
+		Paths: []string{"metadata.labels"},
+	}, FilterEnvironment{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	deployment := newUnstructured("apps/v1", "Deployment", "default", "api")
+	deployment.SetLabels(map[string]string{"app": "api"})
+	g.Expect(filter.Apply(deployment)).To(gomega.Succeed())
+	g.Expect(deployment.GetLabels()).To(gomega.BeEmpty())
+
+	service := newUnstructured("v1", "Service", "default", "api")
+	service.SetLabels(map[string]string{"app": "api"})
+	g.Expect(filter.Apply(service)).To(gomega.Succeed())
+	g.Expect(service.GetLabels()).To(gomega.HaveKey("app"))
+}
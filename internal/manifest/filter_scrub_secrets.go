@@ -0,0 +1,136 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+// secretDetectors holds the built-in detectors named in config.SecretDetectors. A detector with capturing groups
+// redacts only the text of the group that matched, keeping the context around it, such as the key of a password= pair.
+var secretDetectors = map[string]*regexp.Regexp{
+	"awsAccessKey": regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	"bearerToken":  regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/-]{16,}=*)`),
+	"githubToken":  regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`),
+	"jwt":          regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	"passwordPair": regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|secret|api[_-]?key|access[_-]?token)\s*[=:]\s*(?:"([^"]*)"|'([^']*)'|([^\s"'&;,]+))`),
+	"privateKey":   regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	"slackToken":   regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),
+	"urlUserinfo":  regexp.MustCompile(`\b[A-Za-z][A-Za-z0-9+.-]*://[^/\s:@]+:([^/\s@]+)@`),
+}
+
+// redactedPrefix starts the default placeholder and every fingerprint, which must not be redacted again.
+var redactedPrefix = strings.TrimSuffix(config.DefaultRedactedValue, ">")
+
+// identityFields lists the fields that name an object, which are never scrubbed, so that the manifest is still
+// written to the same file.
+var identityFields = map[string][]string{
+	"":         {"apiVersion", "kind"},
+	"metadata": {"name", "namespace", "uid"},
+}
+
+// ScrubSecretsFilter redacts the parts of every string in an object that match a pattern, wherever the string sits:
+// container args and commands, annotations, or connection strings in ConfigMaps.
+type ScrubSecretsFilter struct {
+	Redactor Redactor
+	Patterns []*regexp.Regexp
+}
+
+// NewScrubSecretsFilter builds a filter from the named built-in detectors and more patterns.
+func NewScrubSecretsFilter(redactor Redactor, detectors, patterns []string) (ScrubSecretsFilter, error) {
+	filter := ScrubSecretsFilter{Redactor: redactor}
+	for _, name := range detectors {
+		detector, ok := secretDetectors[name]
+		if !ok {
+			return ScrubSecretsFilter{}, fmt.Errorf("unknown detector %q", name)
+		}
+		filter.Patterns = append(filter.Patterns, detector)
+	}
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return ScrubSecretsFilter{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		filter.Patterns = append(filter.Patterns, compiled)
+	}
+	return filter, nil
+}
+
+// Apply redacts the matches in the object.
+func (f ScrubSecretsFilter) Apply(obj *unstructured.Unstructured) error {
+	_, err := f.ApplyCounting(obj)
+	return err
+}
+
+// ApplyCounting redacts the matches in the object and returns how many it redacted.
+func (f ScrubSecretsFilter) ApplyCounting(obj *unstructured.Unstructured) (int, error) {
+	if obj == nil || len(f.Patterns) == 0 {
+		return 0, nil
+	}
+	_, count := f.scrub(obj.Object, "")
+	return count, nil
+}
+
+// scrub redacts the matches below value. field is "" for the object itself and "metadata" for its metadata, whose
+// identity fields are kept.
+func (f ScrubSecretsFilter) scrub(value interface{}, field string) (interface{}, int) {
+	count := 0
+	switch typed := value.(type) {
+	case string:
+		return f.scrubString(typed)
+	case map[string]interface{}:
+		for key, item := range typed {
+			if slices.Contains(identityFields[field], key) {
+				continue
+			}
+			child := "-"
+			if field == "" {
+				child = key
+			}
+			var redactions int
+			typed[key], redactions = f.scrub(item, child)
+			count += redactions
+		}
+	case []interface{}:
+		for i, item := range typed {
+			var redactions int
+			typed[i], redactions = f.scrub(item, "-")
+			count += redactions
+		}
+	}
+	return value, count
+}
+
+// scrubString redacts every match of every pattern. A match of a pattern with capturing groups only redacts the text
+// of the first group that matched. Text that is already redacted is left alone.
+func (f ScrubSecretsFilter) scrubString(text string) (string, int) {
+	count := 0
+	for _, pattern := range f.Patterns {
+		var scrubbed strings.Builder
+		last := 0
+		for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := match[0], match[1]
+			for group := 2; group+1 < len(match); group += 2 {
+				if match[group] >= 0 {
+					start, end = match[group], match[group+1]
+					break
+				}
+			}
+			if start == end || strings.HasPrefix(text[start:], redactedPrefix) || text[start:end] == f.Redactor.placeholder {
+				continue
+			}
+			scrubbed.WriteString(text[last:start])
+			scrubbed.WriteString(f.Redactor.Redact([]byte(text[start:end])))
+			last = end
+			count++
+		}
+		scrubbed.WriteString(text[last:])
+		text = scrubbed.String()
+	}
+	return text, count
+}
@@ -22,17 +22,18 @@ func (s *stubProcessor) Delete(config.ObjectRule, *unstructured.Unstructured, *c
 	return nil
 }
 
-func TestFilterProcessorRemovesStatus(t *testing.T) {
+func TestRuleFiltersRemoveStatus(t *testing.T) {
 	t.Parallel()
 	g := gomega.NewWithT(t)
 
 	next := &stubProcessor{}
-	processor := NewFilterProcessor(next, RemoveStatusFilter{})
+	processor := NewRuleFilters(next, FilterEnvironment{})
+	rule := config.ObjectRule{Kind: "Pod", Filters: []config.FilterConfig{{Name: config.FilterRemoveStatus}}}
 
 	obj := newUnstructured("v1", "Pod", "default", "api")
 	obj.Object["status"] = map[string]interface{}{"phase": "Running"}
 
-	_, err := processor.Process(config.ObjectRule{Kind: "Pod"}, obj, &config.Config{})
+	_, err := processor.Process(rule, obj, &config.Config{})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	g.Expect(next.lastObj.Object).NotTo(gomega.HaveKey("status"))
 }
@@ -0,0 +1,213 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// wasmPageSize is the size of a page of WebAssembly memory.
+const wasmPageSize = 64 * 1024
+
+// wasmCompilationCache keeps compiled modules for the life of the process, so that a filter rebuilt when the
+// configuration is reloaded does not compile its modules again.
+var wasmCompilationCache = wazero.NewCompilationCache()
+
+// wasmExports lists the exports every module must provide, with their signatures.
+var wasmExports = map[string]struct{ params, results []api.ValueType }{
+	"allocate": {params: []api.ValueType{api.ValueTypeI32}, results: []api.ValueType{api.ValueTypeI32}},
+	"filter":   {params: []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, results: []api.ValueType{api.ValueTypeI64}},
+}
+
+// WasmFilter passes each object, as JSON, through WebAssembly modules run by an in-process runtime. Modules are
+// compiled once, and each object gets a fresh instance of each module, so nothing carries over from one object to the
+// next. A module sees only its own memory: it has no access to the network, the file system, the environment, or the
+// tool's credentials. Its memory and its running time are limited.
+//
+// A module exports its memory as "memory", "allocate(size i32) i32", which returns the address of size bytes the host
+// may write, and "filter(address i32, size i32) i64", which filters the object written there. filter returns the
+// address of the filtered object in its upper 32 bits and its size in the lower 32 bits, or 0 to drop the object.
+// Modules built for WASI, such as Go programs built with GOOS=wasip1 and -buildmode=c-shared, are supported; their
+// "_initialize" function runs before each object.
+//
+// An object a module cannot filter, because it traps, exits, runs out of memory or time, or returns something other
+// than an object, is not written: the returned error matches ErrSkipObject.
+type WasmFilter struct {
+	runtime wazero.Runtime
+	modules []wasmModule
+	timeout time.Duration
+}
+
+// wasmModule is a compiled module and the path it was loaded from.
+type wasmModule struct {
+	name     string
+	compiled wazero.CompiledModule
+}
+
+// NewWasmFilter compiles the modules at the supplied paths, which run in order with at most memoryLimit bytes of
+// memory each, and at most timeout for each object.
+func NewWasmFilter(paths []string, memoryLimit int64, timeout time.Duration) (*WasmFilter, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no WebAssembly modules")
+	}
+	pages := uint32(min(max(memoryLimit/wasmPageSize, 1), 65536))
+	ctx := context.Background()
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(pages).
+		WithCloseOnContextDone(true).
+		WithCompilationCache(wasmCompilationCache))
+	filter := &WasmFilter{runtime: runtime, timeout: timeout}
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		_ = filter.Close()
+		return nil, fmt.Errorf("instantiate WASI: %w", err)
+	}
+	for _, path := range paths {
+		module, err := compileWasmModule(ctx, runtime, path)
+		if err != nil {
+			_ = filter.Close()
+			return nil, err
+		}
+		filter.modules = append(filter.modules, module)
+	}
+	return filter, nil
+}
+
+func compileWasmModule(ctx context.Context, runtime wazero.Runtime, path string) (wasmModule, error) {
+	binary, err := os.ReadFile(path)
+	if err != nil {
+		return wasmModule{}, fmt.Errorf("read WebAssembly module: %w", err)
+	}
+	compiled, err := runtime.CompileModule(ctx, binary)
+	if err != nil {
+		return wasmModule{}, fmt.Errorf("compile WebAssembly module %s: %w", path, err)
+	}
+	// The runtime rejects modules whose memory starts above the limit.
+	if _, ok := compiled.ExportedMemories()["memory"]; !ok {
+		return wasmModule{}, fmt.Errorf("WebAssembly module %s does not export its memory as \"memory\"", path)
+	}
+	functions := compiled.ExportedFunctions()
+	for _, name := range []string{"allocate", "filter"} {
+		function, ok := functions[name]
+		signature := wasmExports[name]
+		if !ok || !slices.Equal(function.ParamTypes(), signature.params) || !slices.Equal(function.ResultTypes(), signature.results) {
+			return wasmModule{}, fmt.Errorf("WebAssembly module %s does not export %s%s", path, name, describeWasmSignature(signature.params, signature.results))
+		}
+	}
+	return wasmModule{name: filepath.Base(path), compiled: compiled}, nil
+}
+
+func describeWasmSignature(params, results []api.ValueType) string {
+	names := func(types []api.ValueType) string {
+		described := make([]string, len(types))
+		for i, valueType := range types {
+			described[i] = api.ValueTypeName(valueType)
+		}
+		return strings.Join(described, ", ")
+	}
+	return fmt.Sprintf("(%s) %s", names(params), names(results))
+}
+
+// Apply runs every module on the object, in order, and replaces the object with the last module's result. It returns
+// ErrDropObject when a module drops the object.
+func (f *WasmFilter) Apply(obj *unstructured.Unstructured) error {
+	if obj == nil {
+		return nil
+	}
+	document, err := json.Marshal(obj.Object)
+	if err != nil {
+		return fmt.Errorf("encode object: %w", err)
+	}
+	for _, module := range f.modules {
+		document, err = f.run(module, document)
+		if err != nil {
+			return err
+		}
+	}
+	var filtered map[string]interface{}
+	if err := utiljson.Unmarshal(document, &filtered); err != nil {
+		return skipError{err: fmt.Errorf("WebAssembly module %s returned something other than an object: %w", f.modules[len(f.modules)-1].name, err)}
+	}
+	obj.Object = filtered
+	return nil
+}
+
+// run passes a document to a fresh instance of the module and returns what it answers.
+func (f *WasmFilter) run(module wasmModule, document []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+	result, err := f.call(ctx, module, document)
+	switch {
+	case errors.Is(err, ErrDropObject):
+		return nil, err
+	case ctx.Err() != nil:
+		return nil, skipError{err: fmt.Errorf("WebAssembly module %s did not finish within %s", module.name, f.timeout)}
+	case err != nil:
+		return nil, skipError{err: fmt.Errorf("WebAssembly module %s: %w", module.name, err)}
+	}
+	return result, nil
+}
+
+func (f *WasmFilter) call(ctx context.Context, module wasmModule, document []byte) ([]byte, error) {
+	// Each instance is anonymous, so that instances of the same module do not clash, and gets no arguments,
+	// environment, file system, or clock beyond what WASI requires. Its stderr is discarded.
+	instance, err := f.runtime.InstantiateModule(ctx, module.compiled, wazero.NewModuleConfig().
+		WithName("").
+		WithStartFunctions("_initialize"))
+	if err != nil {
+		return nil, describeWasmError(err)
+	}
+	defer instance.Close(context.Background())
+
+	results, err := instance.ExportedFunction("allocate").Call(ctx, uint64(len(document)))
+	if err != nil {
+		return nil, describeWasmError(err)
+	}
+	address := uint32(results[0])
+	if !instance.Memory().Write(address, document) {
+		return nil, fmt.Errorf("allocate returned %d bytes at %d, outside its memory", len(document), address)
+	}
+	results, err = instance.ExportedFunction("filter").Call(ctx, uint64(address), uint64(len(document)))
+	if err != nil {
+		return nil, describeWasmError(err)
+	}
+	if results[0] == 0 {
+		return nil, ErrDropObject
+	}
+	address, size := uint32(results[0]>>32), uint32(results[0])
+	output, ok := instance.Memory().Read(address, size)
+	if !ok {
+		return nil, fmt.Errorf("filter returned %d bytes at %d, outside its memory", size, address)
+	}
+	// The output is a view of the instance's memory, which is released when it is closed.
+	return append([]byte(nil), output...), nil
+}
+
+// describeWasmError words the error of a module that exited or trapped on one line, leaving out the stack trace.
+func describeWasmError(err error) error {
+	var exit *sys.ExitError
+	if errors.As(err, &exit) {
+		return fmt.Errorf("exited with code %d", exit.ExitCode())
+	}
+	if message, _, traced := strings.Cut(err.Error(), "\nwasm stack trace:"); traced {
+		return errors.New(message)
+	}
+	return err
+}
+
+// Close releases the runtime and every compiled module.
+func (f *WasmFilter) Close() error {
+	return f.runtime.Close(context.Background())
+}
@@ -0,0 +1,147 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+var testWasm struct {
+	once sync.Once
+	dir  string
+	path string
+	err  error
+}
+
+// testWasmModule builds the module in testdata/wasm/filter once for every test that needs it.
+func testWasmModule(t *testing.T) string {
+	t.Helper()
+	testWasm.once.Do(func() {
+		testWasm.dir, testWasm.err = os.MkdirTemp("", "wasm-filter")
+		if testWasm.err != nil {
+			return
+		}
+		testWasm.path = filepath.Join(testWasm.dir, "filter.wasm")
+		cmd := exec.Command("go", "build", "-buildmode=c-shared", "-o", testWasm.path, ".")
+		cmd.Dir = filepath.Join("testdata", "wasm", "filter")
+		cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			testWasm.err = fmt.Errorf("%w: %s", err, output)
+		}
+	})
+	if testWasm.err != nil {
+		t.Fatalf("build WebAssembly module: %v", testWasm.err)
+	}
+	return testWasm.path
+}
+
+func removeTestWasmModule() {
+	if testWasm.dir != "" {
+		_ = os.RemoveAll(testWasm.dir)
+	}
+}
+
+func newTestWasmFilter(t *testing.T, paths []string, memoryLimit int64, timeout time.Duration) *WasmFilter {
+	t.Helper()
+	filter, err := NewWasmFilter(paths, memoryLimit, timeout)
+	if err != nil {
+		t.Fatalf("build wasm filter: %v", err)
+	}
+	t.Cleanup(func() { _ = filter.Close() })
+	return filter
+}
+
+func TestWasmFilterRunsModulesInOrder(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	module := testWasmModule(t)
+	filter := newTestWasmFilter(t, []string{module, module}, 64<<20, 10*time.Second)
+
+	obj := newUnstructured("v1", "ConfigMap", "prod", "api")
+	obj.Object["data"] = map[string]interface{}{"password": "hunter2"}
+	g.Expect(filter.Apply(obj)).To(gomega.Succeed())
+	g.Expect(obj.Object["data"]).To(gomega.Equal(map[string]interface{}{"password": "<Redacted>"}))
+	g.Expect(obj.GetName()).To(gomega.Equal("api"))
+
+	g.Expect(filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "drop"))).To(gomega.MatchError(ErrDropObject))
+}
+
+func TestWasmFilterSkipsObjectsModulesCannotFilter(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	filter := newTestWasmFilter(t, []string{testWasmModule(t)}, 64<<20, time.Second)
+
+	err := filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "panic"))
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(err).To(gomega.MatchError("WebAssembly module filter.wasm: wasm error: unreachable"))
+
+	err = filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "array"))
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(err.Error()).To(gomega.HavePrefix("WebAssembly module filter.wasm returned something other than an object: "))
+
+	err = filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "loop"))
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(err).To(gomega.MatchError("WebAssembly module filter.wasm did not finish within 1s"))
+
+	// Go modules abort when they run out of memory.
+	err = filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "hog"))
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(err).To(gomega.MatchError("WebAssembly module filter.wasm: wasm error: unreachable"))
+
+	// A fresh instance filters the next object.
+	g.Expect(filter.Apply(newUnstructured("v1", "ConfigMap", "prod", "api"))).To(gomega.Succeed())
+}
+
+func TestNewWasmFilterChecksModules(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.wasm")
+	g.Expect(os.WriteFile(empty, []byte("\x00asm\x01\x00\x00\x00"), 0o600)).To(gomega.Succeed())
+
+	_, err := NewWasmFilter([]string{filepath.Join(dir, "missing.wasm")}, 64<<20, time.Second)
+	g.Expect(err).To(gomega.MatchError(os.ErrNotExist))
+
+	_, err = NewWasmFilter([]string{empty}, 64<<20, time.Second)
+	g.Expect(err).To(gomega.MatchError(`WebAssembly module ` + empty + ` does not export its memory as "memory"`))
+
+	_, err = NewWasmFilter([]string{testWasmModule(t)}, 64<<10, time.Second)
+	g.Expect(err).To(gomega.MatchError(gomega.HaveSuffix("over limit of 1 pages (64 Ki)")))
+}
+
+func TestRuleFiltersRunWasmModules(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	processor := NewRuleFilters(NewWriter(config.OutputConfig{Directory: dir, Format: config.OutputFormatYAML}), FilterEnvironment{})
+	t.Cleanup(func() { _ = processor.Close() })
+	cfg := &config.Config{Filters: []config.FilterConfig{{
+		Name:        config.FilterWasm,
+		Modules:     []string{testWasmModule(t)},
+		MemoryLimit: "32Mi",
+		Timeout:     "5s",
+	}}}
+	rule := config.ObjectRule{APIVersion: "v1", Kind: "ConfigMap"}
+
+	obj := newUnstructured("v1", "ConfigMap", "prod", "api")
+	obj.Object["data"] = map[string]interface{}{"token": "abc"}
+	diff, err := processor.Process(rule, obj, cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(diff.Current.Object["data"]).To(gomega.Equal(map[string]interface{}{"token": "<Redacted>"}))
+
+	_, err = processor.Process(rule, newUnstructured("v1", "ConfigMap", "prod", "panic"), cfg)
+	g.Expect(err).To(gomega.MatchError(ErrSkipObject))
+	g.Expect(filepath.Join(dir, "ConfigMap", "prod", "panic.yaml")).NotTo(gomega.BeAnExistingFile())
+}
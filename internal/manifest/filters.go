@@ -1,7 +1,7 @@
 package manifest
 
 import (
-	"fmt"
+	"errors"
 
 	"github.com/grafana/k8s-manifest-tail/internal/config"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -12,36 +12,30 @@ type Filter interface {
 	Apply(obj *unstructured.Unstructured) error
 }
 
-// FilterProcessor runs filters before delegating to the next processor.
-type FilterProcessor struct {
-	next    Processor
-	filters []Filter
-}
+// ErrDropObject is returned by filters that exclude an object. The object is not written, and a stored copy of it is
+// removed.
+var ErrDropObject = errors.New("object dropped by filter")
 
-// NewFilterProcessor constructs a processor that applies filters before invoking next.
-func NewFilterProcessor(next Processor, filters ...Filter) Processor {
-	return &FilterProcessor{
-		next:    next,
-		filters: filters,
-	}
+// CountingFilter is a Filter that reports how many values it redacted in each object.
+type CountingFilter interface {
+	Filter
+	ApplyCounting(obj *unstructured.Unstructured) (int, error)
 }
 
-// Process applies filters and passes the object to the next processor.
-func (p *FilterProcessor) Process(rule config.ObjectRule, obj *unstructured.Unstructured, cfg *config.Config) (*Diff, error) {
-	for _, filter := range p.filters {
-		if err := filter.Apply(obj); err != nil {
-			return nil, fmt.Errorf("apply filter: %w", err)
-		}
-	}
-	return p.next.Process(rule, obj, cfg)
+// RuleFilter is a CountingFilter that is also told which rule collected each object.
+type RuleFilter interface {
+	CountingFilter
+	ApplyRule(rule config.ObjectRule, obj *unstructured.Unstructured) (int, error)
 }
 
-// Delete applies filters before delegating deletion to the next processor.
-func (p *FilterProcessor) Delete(rule config.ObjectRule, obj *unstructured.Unstructured, cfg *config.Config) error {
-	for _, filter := range p.filters {
-		if err := filter.Apply(obj); err != nil {
-			return fmt.Errorf("apply filter: %w", err)
-		}
+// applyFilter runs a filter for the objects of a rule, returning how many values it redacted when it counts them.
+func applyFilter(filter Filter, rule config.ObjectRule, obj *unstructured.Unstructured) (int, error) {
+	switch typed := filter.(type) {
+	case RuleFilter:
+		return typed.ApplyRule(rule, obj)
+	case CountingFilter:
+		return typed.ApplyCounting(obj)
+	default:
+		return 0, filter.Apply(obj)
 	}
-	return p.next.Delete(rule, obj, cfg)
 }
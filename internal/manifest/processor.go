@@ -9,6 +9,8 @@ import (
 type Diff struct {
 	Previous *unstructured.Unstructured
 	Current  *unstructured.Unstructured
+	// Redactions is the number of values that counting filters redacted in the current object.
+	Redactions int
 }
 
 // Processor handles manifests retrieved from the cluster.
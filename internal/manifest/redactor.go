@@ -0,0 +1,49 @@
+package manifest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+// fingerprintLength is the number of hex digits of the HMAC kept in a fingerprint.
+const fingerprintLength = 16
+
+// Redactor replaces sensitive values, either with a fixed placeholder or with a keyed fingerprint that changes
+// whenever the value does.
+type Redactor struct {
+	placeholder string
+	key         []byte
+}
+
+// NewRedactor builds the redactor configured for a filter.
+func NewRedactor(spec config.FilterConfig) (Redactor, error) {
+	redactor := Redactor{placeholder: spec.Replacement}
+	if redactor.placeholder == "" {
+		redactor.placeholder = config.DefaultRedactedValue
+	}
+	if spec.Hash != nil {
+		key, err := spec.Hash.Key()
+		if err != nil {
+			return Redactor{}, err
+		}
+		redactor.key = key
+	}
+	return redactor, nil
+}
+
+// Redact returns the replacement for a sensitive value.
+func (r Redactor) Redact(value []byte) string {
+	if r.key == nil {
+		if r.placeholder == "" {
+			return config.DefaultRedactedValue
+		}
+		return r.placeholder
+	}
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write(value)
+	return fmt.Sprintf("<Redacted sha256:%s>", hex.EncodeToString(mac.Sum(nil))[:fingerprintLength])
+}
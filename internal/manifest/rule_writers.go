@@ -0,0 +1,48 @@
+package manifest
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+// RuleWriters sends the manifests of each rule to a Writer built from the rule's output settings, which override the
+// default output settings field by field. Rules with the same effective settings share a Writer.
+type RuleWriters struct {
+	defaults config.OutputConfig
+
+	mu      sync.Mutex
+	writers map[config.OutputConfig]*Writer
+}
+
+// NewRuleWriters builds a processor that writes each rule's manifests according to its output settings.
+func NewRuleWriters(defaults config.OutputConfig) *RuleWriters {
+	return &RuleWriters{
+		defaults: defaults,
+		writers:  make(map[config.OutputConfig]*Writer),
+	}
+}
+
+// Process saves the manifest with the writer for the rule.
+func (w *RuleWriters) Process(rule config.ObjectRule, obj *unstructured.Unstructured, cfg *config.Config) (*Diff, error) {
+	return w.writerFor(rule).Process(rule, obj, cfg)
+}
+
+// Delete removes the manifest with the writer for the rule.
+func (w *RuleWriters) Delete(rule config.ObjectRule, obj *unstructured.Unstructured, cfg *config.Config) error {
+	return w.writerFor(rule).Delete(rule, obj, cfg)
+}
+
+func (w *RuleWriters) writerFor(rule config.ObjectRule) *Writer {
+	output := w.defaults.Merge(rule.Output)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	writer, ok := w.writers[output]
+	if !ok {
+		writer = NewWriter(output)
+		w.writers[output] = writer
+	}
+	return writer
+}
@@ -0,0 +1,55 @@
+package manifest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+)
+
+func TestRuleWritersUseRuleOutputOverrides(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	writers := NewRuleWriters(config.OutputConfig{
+		Directory: filepath.Join(dir, "default"),
+		Format:    config.OutputFormatYAML,
+	})
+
+	podRule := config.ObjectRule{Kind: "Pod"}
+	secretRule := config.ObjectRule{Kind: "Secret", Output: &config.OutputConfig{
+		Directory:    filepath.Join(dir, "secrets"),
+		Format:       config.OutputFormatJSON,
+		PathTemplate: "{namespace}/{kind}/{name}",
+	}}
+
+	_, err := writers.Process(podRule, newUnstructured("v1", "Pod", "default", "api"), nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	secret := newUnstructured("v1", "Secret", "default", "token")
+	_, err = writers.Process(secretRule, secret, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(filepath.Join(dir, "default", "Pod", "default", "api.yaml")).To(gomega.BeAnExistingFile())
+	secretPath := filepath.Join(dir, "secrets", "default", "Secret", "token.json")
+	g.Expect(secretPath).To(gomega.BeAnExistingFile())
+
+	g.Expect(writers.Delete(secretRule, secret, nil)).To(gomega.Succeed())
+	g.Expect(secretPath).NotTo(gomega.BeAnExistingFile())
+}
+
+func TestRuleWritersShareWritersForTheSameOutput(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	writers := NewRuleWriters(config.OutputConfig{Directory: t.TempDir(), Format: config.OutputFormatYAML})
+
+	pods := writers.writerFor(config.ObjectRule{Kind: "Pod"})
+	services := writers.writerFor(config.ObjectRule{Kind: "Service", Output: &config.OutputConfig{Format: config.OutputFormatYAML}})
+	secrets := writers.writerFor(config.ObjectRule{Kind: "Secret", Output: &config.OutputConfig{Format: config.OutputFormatJSON}})
+
+	g.Expect(services).To(gomega.BeIdenticalTo(pods))
+	g.Expect(secrets).NotTo(gomega.BeIdenticalTo(pods))
+}
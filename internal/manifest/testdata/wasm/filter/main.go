@@ -0,0 +1,64 @@
+//go:build wasip1
+
+// Command filter is the WebAssembly module of the wasm filter tests. It redacts the data of ConfigMaps, and behaves
+// badly for objects with some names. Build it with:
+//
+//	GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared -o filter.wasm .
+package main
+
+import (
+	"encoding/json"
+	"unsafe"
+)
+
+// buffers keeps the memory handed to the host alive until the module instance is closed.
+var buffers [][]byte
+
+//go:wasmexport allocate
+func allocate(size uint32) uint32 {
+	buffer := make([]byte, size)
+	buffers = append(buffers, buffer)
+	return uint32(uintptr(unsafe.Pointer(unsafe.SliceData(buffer))))
+}
+
+//go:wasmexport filter
+func filter(ptr, size uint32) uint64 {
+	input := unsafe.Slice((*byte)(unsafe.Pointer(uintptr(ptr))), size)
+	var obj map[string]any
+	if err := json.Unmarshal(input, &obj); err != nil {
+		panic(err)
+	}
+	metadata, _ := obj["metadata"].(map[string]any)
+	switch metadata["name"] {
+	case "drop":
+		return 0
+	case "loop":
+		for {
+		}
+	case "panic":
+		panic("cannot filter")
+	case "hog":
+		for {
+			buffers = append(buffers, make([]byte, 1<<20))
+		}
+	case "array":
+		return output([]byte("[]"))
+	}
+	if data, ok := obj["data"].(map[string]any); ok {
+		for key := range data {
+			data[key] = "<Redacted>"
+		}
+	}
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		panic(err)
+	}
+	return output(encoded)
+}
+
+func output(data []byte) uint64 {
+	buffers = append(buffers, data)
+	return uint64(uintptr(unsafe.Pointer(unsafe.SliceData(data))))<<32 | uint64(len(data))
+}
+
+func main() {}
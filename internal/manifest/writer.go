@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/yaml"
 
 	"github.com/grafana/k8s-manifest-tail/internal/config"
@@ -17,15 +18,21 @@ import (
 
 // Writer persists manifests to disk in the desired format.
 type Writer struct {
-	baseDir string
-	format  config.OutputFormat
+	baseDir      string
+	format       config.OutputFormat
+	pathTemplate string
 }
 
 // NewWriter builds a manifest writer for the supplied configuration.
 func NewWriter(cfg config.OutputConfig) *Writer {
+	pathTemplate := cfg.PathTemplate
+	if pathTemplate == "" {
+		pathTemplate = config.DefaultPathTemplate
+	}
 	return &Writer{
-		baseDir: cfg.Directory,
-		format:  cfg.Format,
+		baseDir:      cfg.Directory,
+		format:       cfg.Format,
+		pathTemplate: pathTemplate,
 	}
 }
 
@@ -35,11 +42,7 @@ func (w *Writer) Process(rule config.ObjectRule, obj *unstructured.Unstructured,
 		return nil, err
 	}
 
-	kindDir := filepath.Join(w.baseDir, sanitizePathSegment(rule.Kind))
-	nsDir := filepath.Join(kindDir, sanitizePathSegment(namespaceSegment(obj.GetNamespace())))
-	fileName := fmt.Sprintf("%s.%s", sanitizePathSegment(obj.GetName()), w.extension())
-	path := filepath.Join(nsDir, fileName)
-
+	path := w.manifestPath(rule, obj)
 	prevObj, prevJSON, err := w.loadExisting(path)
 	if err != nil {
 		return nil, err
@@ -63,8 +66,8 @@ func (w *Writer) Process(rule config.ObjectRule, obj *unstructured.Unstructured,
 		return nil, err
 	}
 
-	if err := os.MkdirAll(nsDir, 0o755); err != nil {
-		return nil, fmt.Errorf("create directory %s: %w", nsDir, err)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create directory %s: %w", filepath.Dir(path), err)
 	}
 	if err := os.WriteFile(path, data, 0o644); err != nil {
 		return nil, fmt.Errorf("write manifest %s: %w", path, err)
@@ -81,10 +84,7 @@ func (w *Writer) Delete(rule config.ObjectRule, obj *unstructured.Unstructured,
 	if err := w.ensureBaseDir(); err != nil {
 		return err
 	}
-	kindDir := filepath.Join(w.baseDir, sanitizePathSegment(rule.Kind))
-	nsDir := filepath.Join(kindDir, sanitizePathSegment(namespaceSegment(obj.GetNamespace())))
-	fileName := fmt.Sprintf("%s.%s", sanitizePathSegment(obj.GetName()), w.extension())
-	path := filepath.Join(nsDir, fileName)
+	path := w.manifestPath(rule, obj)
 	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
 		return fmt.Errorf("remove manifest %s: %w", path, err)
 	}
@@ -99,12 +99,17 @@ func (w *Writer) ensureBaseDir() error {
 }
 
 func (w *Writer) serialize(obj *unstructured.Unstructured) ([]byte, error) {
+	return Serialize(obj, w.format)
+}
+
+// Serialize renders an object as a manifest would be written in the supplied format.
+func Serialize(obj *unstructured.Unstructured, format config.OutputFormat) ([]byte, error) {
 	jsonBytes, err := obj.MarshalJSON()
 	if err != nil {
 		return nil, fmt.Errorf("marshal object: %w", err)
 	}
 
-	switch w.format {
+	switch format {
 	case config.OutputFormatJSON:
 		var buf bytes.Buffer
 		if err := json.Indent(&buf, jsonBytes, "", "  "); err != nil {
@@ -122,10 +127,28 @@ func (w *Writer) serialize(obj *unstructured.Unstructured) ([]byte, error) {
 		}
 		return yamlBytes, nil
 	default:
-		return nil, fmt.Errorf("unsupported output format %q", w.format)
+		return nil, fmt.Errorf("unsupported output format %q", format)
 	}
 }
 
+// manifestPath renders the path template for the object. Each placeholder is sanitized so that it stays within a
+// single path segment.
+func (w *Writer) manifestPath(rule config.ObjectRule, obj *unstructured.Unstructured) string {
+	gv, _ := schema.ParseGroupVersion(obj.GetAPIVersion())
+	group := gv.Group
+	if group == "" {
+		group = "core"
+	}
+	relative := strings.NewReplacer(
+		"{group}", sanitizePathSegment(group),
+		"{version}", sanitizePathSegment(gv.Version),
+		"{kind}", sanitizePathSegment(rule.Kind),
+		"{namespace}", sanitizePathSegment(namespaceSegment(obj.GetNamespace())),
+		"{name}", sanitizePathSegment(obj.GetName()),
+	).Replace(w.pathTemplate)
+	return filepath.Join(w.baseDir, filepath.FromSlash(relative)+"."+w.extension())
+}
+
 func (w *Writer) extension() string {
 	if w.format == config.OutputFormatJSON {
 		return "json"
@@ -123,3 +123,29 @@ func TestWriterDeletesManifest(t *testing.T) {
 	path := filepath.Join(dir, "Pod", "default", "api.yaml")
 	g.Expect(path).NotTo(gomega.BeAnExistingFile())
 }
+
+func TestWriterUsesPathTemplate(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dir := t.TempDir()
+	writer := NewWriter(config.OutputConfig{
+		Directory:    dir,
+		Format:       config.OutputFormatJSON,
+		PathTemplate: "{group}/{version}/{kind}/{namespace}/{name}",
+	})
+
+	deployment := newUnstructured("apps/v1", "Deployment", "prod", "frontend")
+	_, err := writer.Process(config.ObjectRule{Kind: "Deployment"}, deployment, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(filepath.Join(dir, "apps", "v1", "Deployment", "prod", "frontend.json")).To(gomega.BeAnExistingFile())
+
+	pod := newUnstructured("v1", "Pod", "default", "api")
+	_, err = writer.Process(config.ObjectRule{Kind: "Pod"}, pod, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	path := filepath.Join(dir, "core", "v1", "Pod", "default", "api.json")
+	g.Expect(path).To(gomega.BeAnExistingFile())
+
+	g.Expect(writer.Delete(config.ObjectRule{Kind: "Pod"}, pod, nil)).To(gomega.Succeed())
+	g.Expect(path).NotTo(gomega.BeAnExistingFile())
+}
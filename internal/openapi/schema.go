@@ -0,0 +1,195 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Document is an OpenAPI v3 document served by the API server for one group version.
+type Document struct {
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// Schema is the subset of an OpenAPI schema needed to find default values.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"-"`
+	Items                *Schema            `json:"items,omitempty"`
+	Default              json.RawMessage    `json:"default,omitempty"`
+	GroupVersionKinds    []struct {
+		Group   string `json:"group"`
+		Version string `json:"version"`
+		Kind    string `json:"kind"`
+	} `json:"x-kubernetes-group-version-kind,omitempty"`
+}
+
+// UnmarshalJSON decodes a schema, ignoring additionalProperties when it is a boolean rather than a schema.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type plain Schema
+	var decoded struct {
+		plain
+		AdditionalProperties json.RawMessage `json:"additionalProperties,omitempty"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*s = Schema(decoded.plain)
+	if len(decoded.AdditionalProperties) > 0 && decoded.AdditionalProperties[0] == '{' {
+		s.AdditionalProperties = &Schema{}
+		if err := json.Unmarshal(decoded.AdditionalProperties, s.AdditionalProperties); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseDocument decodes an OpenAPI v3 document.
+func ParseDocument(data []byte) (*Document, error) {
+	document := &Document{}
+	if err := json.Unmarshal(data, document); err != nil {
+		return nil, fmt.Errorf("decode OpenAPI document: %w", err)
+	}
+	return document, nil
+}
+
+// SchemaFor returns the schema of a kind, or nil when the document does not describe it.
+func (d *Document) SchemaFor(gvk schema.GroupVersionKind) *Schema {
+	for _, candidate := range d.Components.Schemas {
+		for _, described := range candidate.GroupVersionKinds {
+			if described.Group == gvk.Group && described.Version == gvk.Version && described.Kind == gvk.Kind {
+				return candidate
+			}
+		}
+	}
+	return nil
+}
+
+// PruneDefaults removes the fields of obj whose values equal the defaults declared by schema, and returns how many it
+// removed. A field equal to its default is removed as a whole; other fields are pruned recursively.
+func (d *Document) PruneDefaults(schema *Schema, obj map[string]interface{}) int {
+	return d.pruneObject(schema, obj, 0)
+}
+
+// maxDepth bounds the recursion through self-referencing schemas.
+const maxDepth = 64
+
+func (d *Document) pruneObject(schema *Schema, obj map[string]interface{}, depth int) int {
+	properties, additional := d.fields(schema)
+	removed := 0
+	for key, value := range obj {
+		field, ok := properties[key]
+		if !ok {
+			field = additional
+		}
+		if field == nil {
+			continue
+		}
+		if defaultValue := d.defaultOf(field); defaultValue != nil && EqualJSON(value, defaultValue) {
+			delete(obj, key)
+			removed++
+			continue
+		}
+		removed += d.prune(field, value, depth+1)
+	}
+	return removed
+}
+
+func (d *Document) prune(schema *Schema, value interface{}, depth int) int {
+	if depth > maxDepth {
+		return 0
+	}
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return d.pruneObject(schema, typed, depth)
+	case []interface{}:
+		items := d.itemsOf(schema)
+		if items == nil {
+			return 0
+		}
+		removed := 0
+		for _, item := range typed {
+			removed += d.prune(items, item, depth+1)
+		}
+		return removed
+	default:
+		return 0
+	}
+}
+
+// fields collects the properties of a schema across its references and allOf parts.
+func (d *Document) fields(schema *Schema) (map[string]*Schema, *Schema) {
+	properties := make(map[string]*Schema)
+	var additional *Schema
+	d.visit(schema, 0, func(part *Schema) {
+		for name, property := range part.Properties {
+			properties[name] = property
+		}
+		if part.AdditionalProperties != nil {
+			additional = part.AdditionalProperties
+		}
+	})
+	return properties, additional
+}
+
+func (d *Document) itemsOf(schema *Schema) *Schema {
+	var items *Schema
+	d.visit(schema, 0, func(part *Schema) {
+		if part.Items != nil {
+			items = part.Items
+		}
+	})
+	return items
+}
+
+// defaultOf returns the default declared by a schema or the schemas it references, or nil when there is none.
+func (d *Document) defaultOf(schema *Schema) json.RawMessage {
+	var defaultValue json.RawMessage
+	d.visit(schema, 0, func(part *Schema) {
+		if defaultValue == nil && len(part.Default) > 0 {
+			defaultValue = part.Default
+		}
+	})
+	return defaultValue
+}
+
+// visit calls fn for the schema and, depth first, for the schemas it references and combines with allOf.
+func (d *Document) visit(schema *Schema, depth int, fn func(*Schema)) {
+	if schema == nil || depth > maxDepth {
+		return
+	}
+	fn(schema)
+	if schema.Ref != "" {
+		d.visit(d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")], depth+1, fn)
+	}
+	for _, part := range schema.AllOf {
+		d.visit(part, depth+1, fn)
+	}
+}
+
+// EqualJSON reports whether two values, decoded or raw JSON, encode to the same JSON document, so that numbers compare
+// equal whatever their Go type or notation, and objects whatever the order of their keys.
+func EqualJSON(a, b interface{}) bool {
+	normalizedA, errA := normalizeJSON(a)
+	normalizedB, errB := normalizeJSON(b)
+	return errA == nil && errB == nil && normalizedA == normalizedB
+}
+
+func normalizeJSON(value interface{}) (string, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return "", err
+	}
+	normalized, err := json.Marshal(decoded)
+	return string(normalized), err
+}
@@ -0,0 +1,19 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestEqualJSON(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	g.Expect(EqualJSON(int64(1), float64(1))).To(gomega.BeTrue())
+	g.Expect(EqualJSON(int64(1), json.RawMessage(`1.0`))).To(gomega.BeTrue())
+	g.Expect(EqualJSON(map[string]interface{}{"a": int64(1), "b": "x"}, json.RawMessage(`{"b": "x", "a": 1}`))).To(gomega.BeTrue())
+	g.Expect(EqualJSON("1", int64(1))).To(gomega.BeFalse())
+	g.Expect(EqualJSON(nil, json.RawMessage(`{}`))).To(gomega.BeFalse())
+}
@@ -0,0 +1,256 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/openapi"
+
+	"github.com/grafana/k8s-manifest-tail/internal/telemetry"
+)
+
+// pathsRefreshPeriod is how long the list of OpenAPI documents is reused before the API server is asked again, so that
+// new CRDs and upgraded versions are picked up.
+const pathsRefreshPeriod = 10 * time.Minute
+
+// DefaultCacheDirectory returns the directory where OpenAPI documents are cached when no directory is configured.
+func DefaultCacheDirectory() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "k8s-manifest-tail", "openapi")
+}
+
+// Source serves the OpenAPI v3 documents of a cluster, caching them on disk. A document is fetched again only when the
+// API server publishes a new version of it, which it signals by changing the document URL. Without a client, or when
+// the API server cannot be reached, Source serves the cached documents. A failed fetch is not retried for a while, and
+// each failure is logged once as a warning.
+type Source struct {
+	client   openapi.Client
+	cacheDir string
+	logger   log.Logger
+
+	mu          sync.Mutex
+	paths       map[string]openapi.GroupVersion
+	pathsLoaded time.Time
+	documents   map[string]cachedDocument
+	failures    map[string]failedFetch
+	fetching    map[string]*sync.Mutex
+	warned      map[string]struct{}
+}
+
+type cachedDocument struct {
+	url      string
+	document *Document
+}
+
+// failedFetch records a document URL that could not be fetched, so that it is not asked for again until the refresh
+// period has passed.
+type failedFetch struct {
+	url string
+	at  time.Time
+}
+
+// NewSource returns a Source reading documents from client, which may be nil, and caching them in cacheDir, or in
+// DefaultCacheDirectory when cacheDir is empty. Failures are logged to logger; without one, they are not reported.
+func NewSource(client openapi.Client, cacheDir string, logger log.Logger) *Source {
+	if cacheDir == "" {
+		cacheDir = DefaultCacheDirectory()
+	}
+	return &Source{
+		client:    client,
+		cacheDir:  cacheDir,
+		logger:    logger,
+		documents: make(map[string]cachedDocument),
+		failures:  make(map[string]failedFetch),
+		fetching:  make(map[string]*sync.Mutex),
+		warned:    make(map[string]struct{}),
+	}
+}
+
+// Document returns the OpenAPI document of a group version, or nil when neither the API server nor the cache has it.
+// Documents of different group versions are fetched concurrently, while requests for the same one wait for a single
+// fetch.
+func (s *Source) Document(gv schema.GroupVersion) (*Document, error) {
+	path := groupVersionPath(gv)
+	lock := s.fetchLock(path)
+	lock.Lock()
+	defer lock.Unlock()
+
+	groupVersion, ok := s.currentPaths()[path]
+	if !ok {
+		return s.cachedDocument(path)
+	}
+	url := groupVersion.ServerRelativeURL()
+	if document, ok := s.memoized(path, url); ok {
+		return document, nil
+	}
+	if data, err := os.ReadFile(s.cacheFile(path, ".url")); err == nil && string(data) == url {
+		if document, err := s.readCache(path); err == nil {
+			s.memoize(path, url, document)
+			return document, nil
+		}
+	}
+	if s.failedRecently(path, url) {
+		return s.cachedDocument(path)
+	}
+
+	data, err := groupVersion.Schema("application/json")
+	if err != nil {
+		s.recordFailure(path, url, fmt.Errorf("fetch OpenAPI document for %s: %w", gv, err))
+		return s.cachedDocument(path)
+	}
+	document, err := ParseDocument(data)
+	if err != nil {
+		s.recordFailure(path, url, fmt.Errorf("%s: %w", gv, err))
+		return s.cachedDocument(path)
+	}
+	s.memoize(path, url, document)
+	if err := s.writeCache(path, url, data); err != nil {
+		s.warnOnce(err)
+	}
+	return document, nil
+}
+
+// cachedDocument returns the document last served for a path, whatever its version, or the one cached on disk.
+func (s *Source) cachedDocument(path string) (*Document, error) {
+	s.mu.Lock()
+	cached, ok := s.documents[path]
+	s.mu.Unlock()
+	if ok {
+		return cached.document, nil
+	}
+	document, err := s.readCache(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.memoize(path, "", document)
+	return document, nil
+}
+
+func (s *Source) fetchLock(path string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock, ok := s.fetching[path]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.fetching[path] = lock
+	}
+	return lock
+}
+
+func (s *Source) memoized(path, url string) (*Document, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cached, ok := s.documents[path]
+	return cached.document, ok && cached.url == url
+}
+
+// memoize keeps the document served for a path. A document of a known version also ends any earlier failure to fetch
+// the path.
+func (s *Source) memoize(path, url string, document *Document) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.documents[path] = cachedDocument{url: url, document: document}
+	if url != "" {
+		delete(s.failures, path)
+	}
+}
+
+func (s *Source) failedRecently(path, url string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	failure, ok := s.failures[path]
+	return ok && failure.url == url && time.Since(failure.at) < pathsRefreshPeriod
+}
+
+func (s *Source) recordFailure(path, url string, err error) {
+	s.mu.Lock()
+	s.failures[path] = failedFetch{url: url, at: time.Now()}
+	s.mu.Unlock()
+	s.warnOnce(err)
+}
+
+// warnOnce logs an error as a warning, unless the same error was logged before.
+func (s *Source) warnOnce(err error) {
+	if s.logger == nil {
+		return
+	}
+	s.mu.Lock()
+	_, warned := s.warned[err.Error()]
+	s.warned[err.Error()] = struct{}{}
+	s.mu.Unlock()
+	if !warned {
+		telemetry.Warn(s.logger, err.Error())
+	}
+}
+
+// currentPaths returns the documents the API server publishes, asking it again once the refresh period has passed.
+// It returns nil when there is no client or the API server has never been reached.
+func (s *Source) currentPaths() map[string]openapi.GroupVersion {
+	if s.client == nil {
+		return nil
+	}
+	s.mu.Lock()
+	paths, loaded := s.paths, s.pathsLoaded
+	s.mu.Unlock()
+	if !loaded.IsZero() && time.Since(loaded) < pathsRefreshPeriod {
+		return paths
+	}
+
+	fetched, err := s.client.Paths()
+	s.mu.Lock()
+	s.pathsLoaded = time.Now()
+	if err == nil {
+		s.paths = fetched
+	}
+	paths = s.paths
+	s.mu.Unlock()
+	if err != nil {
+		s.warnOnce(fmt.Errorf("list OpenAPI documents: %w", err))
+	}
+	return paths
+}
+
+func (s *Source) readCache(path string) (*Document, error) {
+	data, err := os.ReadFile(s.cacheFile(path, ".json"))
+	if err != nil {
+		return nil, err
+	}
+	return ParseDocument(data)
+}
+
+func (s *Source) writeCache(path, url string, data []byte) error {
+	if err := os.MkdirAll(s.cacheDir, 0o755); err != nil {
+		return fmt.Errorf("create OpenAPI cache directory: %w", err)
+	}
+	if err := os.WriteFile(s.cacheFile(path, ".json"), data, 0o644); err != nil {
+		return fmt.Errorf("write OpenAPI cache: %w", err)
+	}
+	if err := os.WriteFile(s.cacheFile(path, ".url"), []byte(url), 0o644); err != nil {
+		return fmt.Errorf("write OpenAPI cache: %w", err)
+	}
+	return nil
+}
+
+func (s *Source) cacheFile(path, extension string) string {
+	return filepath.Join(s.cacheDir, strings.ReplaceAll(path, "/", "__")+extension)
+}
+
+// groupVersionPath returns the OpenAPI discovery path of a group version, such as api/v1 or apis/apps/v1.
+func groupVersionPath(gv schema.GroupVersion) string {
+	if gv.Group == "" {
+		return "api/" + gv.Version
+	}
+	return "apis/" + gv.Group + "/" + gv.Version
+}
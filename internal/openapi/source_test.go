@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/openapi"
+)
+
+type stubLogger struct {
+	embedded.Logger
+	mu      sync.Mutex
+	records []log.Record
+}
+
+func (s *stubLogger) Emit(_ context.Context, record log.Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+}
+
+func (s *stubLogger) Enabled(context.Context, log.EnabledParameters) bool {
+	return true
+}
+
+// warnings returns the messages logged as warnings.
+func (s *stubLogger) warnings() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var messages []string
+	for _, record := range s.records {
+		if record.Severity() == log.SeverityWarn {
+			messages = append(messages, record.Body().AsString())
+		}
+	}
+	return messages
+}
+
+type stubClient struct {
+	document *stubGroupVersion
+}
+
+func (c *stubClient) Paths() (map[string]openapi.GroupVersion, error) {
+	return map[string]openapi.GroupVersion{"apis/example.com/v1": c.document}, nil
+}
+
+type stubGroupVersion struct {
+	url     string
+	data    string
+	err     error
+	fetches int
+}
+
+func (g *stubGroupVersion) Schema(string) ([]byte, error) {
+	g.fetches++
+	if g.err != nil {
+		return nil, g.err
+	}
+	return []byte(g.data), nil
+}
+
+func (g *stubGroupVersion) ServerRelativeURL() string {
+	return g.url
+}
+
+func widgetDocument(defaultMode string) string {
+	return `{"components": {"schemas": {"Widget": {
+		"properties": {"mode": {"type": "string", "default": "` + defaultMode + `"}},
+		"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}]
+	}}}}`
+}
+
+func TestSourceFetchesNewVersionsOnly(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	gv := schema.GroupVersion{Group: "example.com", Version: "v1"}
+	cacheDir := t.TempDir()
+	published := &stubGroupVersion{url: "/openapi/v3/apis/example.com/v1?hash=1", data: widgetDocument("fast")}
+
+	document, err := NewSource(&stubClient{document: published}, cacheDir, nil).Document(gv)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(document.SchemaFor(gv.WithKind("Widget"))).NotTo(gomega.BeNil())
+	g.Expect(published.fetches).To(gomega.Equal(1))
+
+	// A new process reuses the cached document while the URL is unchanged.
+	_, err = NewSource(&stubClient{document: published}, cacheDir, nil).Document(gv)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(published.fetches).To(gomega.Equal(1))
+
+	published.url = "/openapi/v3/apis/example.com/v1?hash=2"
+	published.data = widgetDocument("slow")
+	document, err = NewSource(&stubClient{document: published}, cacheDir, nil).Document(gv)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(published.fetches).To(gomega.Equal(2))
+
+	obj := map[string]interface{}{"mode": "slow"}
+	g.Expect(document.PruneDefaults(document.SchemaFor(gv.WithKind("Widget")), obj)).To(gomega.Equal(1))
+	g.Expect(obj).To(gomega.BeEmpty())
+
+	// Without a client, the cached document is served.
+	document, err = NewSource(nil, cacheDir, nil).Document(gv)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(document).NotTo(gomega.BeNil())
+
+	document, err = NewSource(nil, cacheDir, nil).Document(schema.GroupVersion{Group: "other.example.com", Version: "v1"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(document).To(gomega.BeNil())
+}
+
+func TestSourceKeepsDocumentsItCannotCache(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	gv := schema.GroupVersion{Group: "example.com", Version: "v1"}
+	// The cache directory cannot be created, because a file is in the way.
+	blocked := filepath.Join(t.TempDir(), "blocked")
+	g.Expect(os.WriteFile(blocked, nil, 0o600)).To(gomega.Succeed())
+	published := &stubGroupVersion{url: "/openapi/v3/apis/example.com/v1?hash=1", data: widgetDocument("fast")}
+	logger := &stubLogger{}
+	source := NewSource(&stubClient{document: published}, filepath.Join(blocked, "openapi"), logger)
+
+	for range 3 {
+		document, err := source.Document(gv)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(document.SchemaFor(gv.WithKind("Widget"))).NotTo(gomega.BeNil())
+	}
+	g.Expect(published.fetches).To(gomega.Equal(1))
+	g.Expect(logger.warnings()).To(gomega.ConsistOf(gomega.HavePrefix("create OpenAPI cache directory: ")))
+}
+
+func TestSourceDoesNotRetryFailedFetches(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	gv := schema.GroupVersion{Group: "example.com", Version: "v1"}
+	cacheDir := t.TempDir()
+	published := &stubGroupVersion{url: "/openapi/v3/apis/example.com/v1?hash=1", data: widgetDocument("fast")}
+	_, err := NewSource(&stubClient{document: published}, cacheDir, nil).Document(gv)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	published.url = "/openapi/v3/apis/example.com/v1?hash=2"
+	published.err = errors.New("connection refused")
+	published.fetches = 0
+	logger := &stubLogger{}
+	source := NewSource(&stubClient{document: published}, cacheDir, logger)
+
+	// The document cached for the previous version is served instead.
+	for range 3 {
+		document, err := source.Document(gv)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(document).NotTo(gomega.BeNil())
+	}
+	g.Expect(published.fetches).To(gomega.Equal(1))
+	g.Expect(logger.warnings()).To(gomega.Equal([]string{"fetch OpenAPI document for example.com/v1: connection refused"}))
+}
@@ -0,0 +1,60 @@
+package schedule
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Never disables a schedule.
+const Never = "never"
+
+// Schedule reports when a recurring job is next due.
+type Schedule interface {
+	// Next returns the first time after t that the job is due.
+	Next(t time.Time) time.Time
+}
+
+// Every runs a job at a fixed interval after the previous run.
+type Every time.Duration
+
+// Next returns t plus the interval.
+func (e Every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Parse reads a schedule expression: a Go duration such as "1h", a five-field cron expression such as "0 * * * *" or a
+// descriptor such as "@daily", or "never". A nil Schedule is returned for "never".
+func Parse(expression string) (Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if strings.EqualFold(expression, Never) {
+		return nil, nil
+	}
+	if duration, err := time.ParseDuration(expression); err == nil {
+		if duration <= 0 {
+			return nil, fmt.Errorf("interval %q must be positive, use %q to disable", expression, Never)
+		}
+		return Every(duration), nil
+	}
+	if strings.HasPrefix(expression, "@") || strings.Contains(expression, " ") {
+		parsed, err := cronParser.Parse(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expression, err)
+		}
+		return parsed, nil
+	}
+	return nil, fmt.Errorf("%q is not a duration, a cron expression, or %q", expression, Never)
+}
+
+// Jitter returns a random delay in [0, max), or zero when max is not positive.
+func Jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return rand.N(max)
+}
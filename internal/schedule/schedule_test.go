@@ -0,0 +1,83 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.March, 4, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		expression string
+		next       time.Time
+	}{
+		{expression: "90m", next: start.Add(90 * time.Minute)},
+		{expression: " 1h ", next: start.Add(time.Hour)},
+		{expression: "0 * * * *", next: time.Date(2026, time.March, 4, 11, 0, 0, 0, time.UTC)},
+		{expression: "*/15 * * * *", next: time.Date(2026, time.March, 4, 10, 30, 0, 0, time.UTC)},
+		{expression: "@daily", next: time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewWithT(t)
+
+			parsed, err := Parse(tt.expression)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(parsed.Next(start)).To(gomega.Equal(tt.next))
+		})
+	}
+}
+
+func TestParseNever(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	for _, expression := range []string{"never", "Never"} {
+		parsed, err := Parse(expression)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(parsed).To(gomega.BeNil())
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"0s":           `interval "0s" must be positive, use "never" to disable`,
+		"-1h":          `interval "-1h" must be positive, use "never" to disable`,
+		"soon":         `"soon" is not a duration, a cron expression, or "never"`,
+		"61 * * * *":   `invalid cron expression "61 * * * *"`,
+		"@fortnightly": `invalid cron expression "@fortnightly"`,
+	}
+
+	for expression, message := range tests {
+		t.Run(expression, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewWithT(t)
+
+			_, err := Parse(expression)
+			g.Expect(err).To(gomega.HaveOccurred())
+			g.Expect(err.Error()).To(gomega.HavePrefix(message))
+		})
+	}
+}
+
+func TestJitter(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	g.Expect(Jitter(0)).To(gomega.BeZero())
+	g.Expect(Jitter(-time.Second)).To(gomega.BeZero())
+	for range 100 {
+		g.Expect(Jitter(time.Minute)).To(gomega.And(
+			gomega.BeNumerically(">=", 0),
+			gomega.BeNumerically("<", time.Minute),
+		))
+	}
+}
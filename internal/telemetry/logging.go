@@ -65,6 +65,14 @@ func Info(logger log.Logger, msg string, attributes ...log.KeyValue) {
 	logger.Emit(context.Background(), record)
 }
 
+func Warn(logger log.Logger, msg string, attributes ...log.KeyValue) {
+	var record log.Record
+	record.SetSeverity(log.SeverityWarn)
+	record.SetBody(log.StringValue(msg))
+	record.AddAttributes(attributes...)
+	logger.Emit(context.Background(), record)
+}
+
 const (
 	OTLPProtocolGRPC         string = "grpc"
 	OTLPProtocolHTTPJSON     string = "http/json"
@@ -2,21 +2,30 @@ package pkg
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/grafana/k8s-manifest-tail/internal/config"
-	"github.com/grafana/k8s-manifest-tail/internal/discovery"
-	"github.com/grafana/k8s-manifest-tail/internal/kube"
-	"github.com/grafana/k8s-manifest-tail/internal/logging"
-	"github.com/grafana/k8s-manifest-tail/internal/manifest"
-	"github.com/grafana/k8s-manifest-tail/internal/telemetry"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
-	"sync"
+
+	"github.com/grafana/k8s-manifest-tail/internal/config"
+	"github.com/grafana/k8s-manifest-tail/internal/discovery"
+	"github.com/grafana/k8s-manifest-tail/internal/kube"
+	"github.com/grafana/k8s-manifest-tail/internal/logging"
+	"github.com/grafana/k8s-manifest-tail/internal/manifest"
+	"github.com/grafana/k8s-manifest-tail/internal/schedule"
+	"github.com/grafana/k8s-manifest-tail/internal/telemetry"
 )
 
 type Tail struct {
@@ -26,29 +35,64 @@ type Tail struct {
 	ManifestLogger logging.DiffLogger
 	Processor      manifest.Processor
 	Metrics        telemetry.MetricsRecorder
+	// Logger, when set, receives a message after each scheduled refresh, and a warning for each skipped object.
+	Logger log.Logger
+
+	mu          sync.RWMutex
+	subscribers []chan struct{}
+	refreshing  map[string]struct{}
+	// processorUsers counts the refreshes and watch events that are using the current processor, so that a processor
+	// replaced by Reload is closed only once they return.
+	processorUsers *sync.WaitGroup
 }
 
+// RunFullManifestCheck lists every rule once and processes the objects found. Rules that are already being refreshed
+// are skipped.
 func (t *Tail) RunFullManifestCheck(ctx context.Context) (int, error) {
-	fetcher := discovery.NewFetcher(t.Clients, t.Config)
+	cfg, _ := t.current()
 	var total int
-	for _, rule := range t.Config.Objects {
-		objects, err := fetcher.FetchResources(ctx, rule)
+	for _, rule := range cfg.Objects {
+		count, err := t.refreshRule(ctx, rule)
+		total += count
 		if err != nil {
 			return total, err
 		}
-		for i := range objects {
-			obj := objects[i].DeepCopy()
-			total++
-			diff, err := t.Processor.Process(rule, obj, t.Config)
-			if err != nil {
-				return total, fmt.Errorf("process %s %s/%s: %w", rule.Kind, obj.GetNamespace(), obj.GetName(), err)
-			}
-			t.DiffLogger.Log(diff)
-			if t.ManifestLogger != nil {
-				t.ManifestLogger.Log(diff)
-			}
-			t.recordDiffMetrics(ctx, diff)
+	}
+	return total, nil
+}
+
+// refreshRule lists the objects of one rule and processes them, unless a refresh of the same rule is still running.
+func (t *Tail) refreshRule(ctx context.Context, rule config.ObjectRule) (int, error) {
+	cfg, processor, release := t.acquire()
+	defer release()
+	key := watchKey(rule, cfg)
+	if !t.beginRefresh(key) {
+		t.logInfo(fmt.Sprintf("Skipping refresh of %s %s: the previous refresh is still running", rule.APIVersion, rule.Kind))
+		return 0, nil
+	}
+	defer t.endRefresh(key)
+
+	objects, err := discovery.NewFetcher(t.Clients, cfg).FetchResources(ctx, rule)
+	if err != nil {
+		return 0, err
+	}
+	var total int
+	for i := range objects {
+		obj := objects[i].DeepCopy()
+		total++
+		diff, err := processor.Process(rule, obj, cfg)
+		if errors.Is(err, manifest.ErrSkipObject) {
+			t.logSkipped(rule, obj, err)
+			continue
+		}
+		if err != nil {
+			return total, fmt.Errorf("process %s %s/%s: %w", rule.Kind, obj.GetNamespace(), obj.GetName(), err)
 		}
+		t.DiffLogger.Log(diff)
+		if t.ManifestLogger != nil {
+			t.ManifestLogger.Log(diff)
+		}
+		t.recordDiffMetrics(ctx, diff)
 	}
 	if t.Metrics != nil {
 		t.Metrics.RecordFullRun(ctx, total)
@@ -56,37 +100,286 @@ func (t *Tail) RunFullManifestCheck(ctx context.Context) (int, error) {
 	return total, nil
 }
 
+func (t *Tail) beginRefresh(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, running := t.refreshing[key]; running {
+		return false
+	}
+	if t.refreshing == nil {
+		t.refreshing = make(map[string]struct{})
+	}
+	t.refreshing[key] = struct{}{}
+	return true
+}
+
+func (t *Tail) endRefresh(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.refreshing, key)
+}
+
+func (t *Tail) logInfo(msg string) {
+	if t.Logger != nil {
+		telemetry.Info(t.Logger, msg)
+	}
+}
+
+// logSkipped warns about an object that was not written because it could not be processed as configured.
+func (t *Tail) logSkipped(rule config.ObjectRule, obj *unstructured.Unstructured, err error) {
+	if t.Logger != nil {
+		telemetry.Warn(t.Logger, fmt.Sprintf("Skipping %s %s/%s: %v", rule.Kind, obj.GetNamespace(), obj.GetName(), err))
+	}
+}
+
+// RunScheduledRefreshes lists each rule again on its own refresh schedule until the context is cancelled or a refresh
+// fails, following configuration changes delivered through Reload. Rules whose refresh interval is "never" are skipped.
+func (t *Tail) RunScheduledRefreshes(ctx context.Context) error {
+	return t.runPerRule(ctx, refreshKey, t.refreshOnSchedule)
+}
+
+func (t *Tail) refreshOnSchedule(ctx context.Context, rule config.ObjectRule) error {
+	cfg, _ := t.current()
+	refreshSchedule, err := cfg.GetRefreshSchedule(rule)
+	if err != nil {
+		return err
+	}
+	if refreshSchedule == nil {
+		return nil
+	}
+	jitter, err := cfg.GetRefreshJitter(rule)
+	if err != nil {
+		return err
+	}
+	for {
+		next := refreshSchedule.Next(time.Now()).Add(schedule.Jitter(jitter))
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		total, err := t.refreshRule(ctx, rule)
+		if err != nil {
+			return err
+		}
+		t.logInfo(fmt.Sprintf("Fetched %d manifest(s) for %s %s", total, rule.APIVersion, rule.Kind))
+	}
+}
+
+// Reload swaps in a new configuration and processor. When WatchResources or RunScheduledRefreshes are running, the
+// watches and schedules of unchanged rules keep running, while those of removed or changed rules are cancelled and new
+// rules start. A replaced processor that implements io.Closer, such as one running plugins, is closed once the
+// refreshes and watch events still using it return.
+func (t *Tail) Reload(cfg *config.Config, processor manifest.Processor) {
+	t.mu.Lock()
+	previous, users := t.Processor, t.processorUsers
+	t.Config = cfg
+	t.Processor = processor
+	if processor != previous {
+		t.processorUsers = nil
+	}
+	subscribers := t.subscribers
+	t.mu.Unlock()
+
+	if processor != previous {
+		go retireProcessor(previous, users)
+	}
+
+	for _, reloaded := range subscribers {
+		select {
+		case reloaded <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// CurrentProcessor returns the processor in use, which Reload may have replaced since the tail was built.
+func (t *Tail) CurrentProcessor() manifest.Processor {
+	_, processor := t.current()
+	return processor
+}
+
+func (t *Tail) current() (*config.Config, manifest.Processor) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.Config, t.Processor
+}
+
+// acquire returns the current configuration and processor, along with a function to call once the processor is no
+// longer used.
+func (t *Tail) acquire() (*config.Config, manifest.Processor, func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.processorUsers == nil {
+		t.processorUsers = &sync.WaitGroup{}
+	}
+	users := t.processorUsers
+	users.Add(1)
+	return t.Config, t.Processor, users.Done
+}
+
+// retireProcessor closes a processor replaced by Reload once its users return.
+func retireProcessor(processor manifest.Processor, users *sync.WaitGroup) {
+	if users != nil {
+		users.Wait()
+	}
+	if closer, ok := processor.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+// subscribe returns a channel that is signalled after each Reload, along with a function that stops the signals.
+func (t *Tail) subscribe() (<-chan struct{}, func()) {
+	reloaded := make(chan struct{}, 1)
+	t.mu.Lock()
+	t.subscribers = append(t.subscribers, reloaded)
+	t.mu.Unlock()
+	return reloaded, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.subscribers = slices.DeleteFunc(t.subscribers, func(c chan struct{}) bool { return c == reloaded })
+	}
+}
+
+// WatchResources watches every configured rule until the context is cancelled or a watch fails, following
+// configuration changes delivered through Reload.
 func (t *Tail) WatchResources(ctx context.Context) error {
-	errCh := make(chan error, len(t.Config.Objects))
-	var wg sync.WaitGroup
-	for _, rule := range t.Config.Objects {
-		rule := rule
-		wg.Add(1)
+	return t.runPerRule(ctx, watchKey, t.watchRule)
+}
+
+// runPerRule runs one task per configured rule until the context is cancelled or a task fails. After each Reload, the
+// tasks of rules whose key changed are restarted.
+func (t *Tail) runPerRule(ctx context.Context, key ruleKeyFunc, run func(ctx context.Context, rule config.ObjectRule) error) error {
+	reloaded, unsubscribe := t.subscribe()
+	defer unsubscribe()
+
+	tasks := newRuleTasks(ctx, key, run)
+	defer tasks.stopAll()
+
+	cfg, _ := t.current()
+	tasks.sync(cfg)
+	for {
+		select {
+		case err := <-tasks.errCh:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-reloaded:
+			cfg, _ := t.current()
+			tasks.sync(cfg)
+		}
+	}
+}
+
+// ruleKeyFunc identifies a rule together with the settings that affect its task, so that editing them restarts it.
+type ruleKeyFunc func(rule config.ObjectRule, cfg *config.Config) string
+
+// ruleTasks tracks the running task for each rule, keyed by a ruleKeyFunc. It is not safe for concurrent use; only
+// the goroutine running runPerRule may change it.
+type ruleTasks struct {
+	ctx   context.Context
+	key   ruleKeyFunc
+	run   func(ctx context.Context, rule config.ObjectRule) error
+	errCh chan error
+	wg    sync.WaitGroup
+	tasks map[string]ruleTask
+}
+
+// ruleTask is a running task: cancel stops it, and done is closed once it has returned.
+type ruleTask struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newRuleTasks(ctx context.Context, key ruleKeyFunc, run func(ctx context.Context, rule config.ObjectRule) error) *ruleTasks {
+	return &ruleTasks{
+		ctx:   ctx,
+		key:   key,
+		run:   run,
+		errCh: make(chan error, 1),
+		tasks: make(map[string]ruleTask),
+	}
+}
+
+// sync cancels the tasks of rules that are gone or changed, waits for them to return, and then starts tasks for rules
+// that are new or changed, so that the old and new task of a rule never run at the same time.
+func (w *ruleTasks) sync(cfg *config.Config) {
+	desired := make(map[string]config.ObjectRule, len(cfg.Objects))
+	for _, rule := range cfg.Objects {
+		desired[w.key(rule, cfg)] = rule
+	}
+	var stopped []ruleTask
+	for key, task := range w.tasks {
+		if _, ok := desired[key]; !ok {
+			task.cancel()
+			stopped = append(stopped, task)
+			delete(w.tasks, key)
+		}
+	}
+	for _, task := range stopped {
+		<-task.done
+	}
+	for key, rule := range desired {
+		if _, running := w.tasks[key]; running {
+			continue
+		}
+		ruleCtx, cancel := context.WithCancel(w.ctx)
+		task := ruleTask{cancel: cancel, done: make(chan struct{})}
+		w.tasks[key] = task
+		w.wg.Add(1)
 		go func() {
-			defer wg.Done()
-			if err := t.watchRule(ctx, rule); err != nil && !errors.Is(err, context.Canceled) {
+			defer w.wg.Done()
+			defer close(task.done)
+			if err := w.run(ruleCtx, rule); err != nil && !errors.Is(err, context.Canceled) {
 				select {
-				case errCh <- err:
+				case w.errCh <- err:
 				default:
 				}
 			}
 		}()
 	}
+}
 
-	done := make(chan struct{})
-	go func() {
-		wg.Wait()
-		close(done)
-	}()
+// stopAll cancels every task and waits for them to return.
+func (w *ruleTasks) stopAll() {
+	for key, task := range w.tasks {
+		task.cancel()
+		delete(w.tasks, key)
+	}
+	w.wg.Wait()
+}
 
-	select {
-	case err := <-errCh:
-		return err
-	case <-done:
-		return ctx.Err()
-	case <-ctx.Done():
-		return ctx.Err()
+// watchKey identifies a rule together with the global settings that change what it watches. The refresh schedule is
+// left out, so changing it does not restart the rule's watch.
+func watchKey(rule config.ObjectRule, cfg *config.Config) string {
+	rule.RefreshInterval = ""
+	rule.RefreshJitter = ""
+	key, _ := json.Marshal(struct {
+		Rule              config.ObjectRule
+		Namespaces        []string
+		ExcludeNamespaces []string
+		NamespaceSelector string
+	}{rule, cfg.Namespaces, cfg.ExcludeNamespaces, cfg.NamespaceSelector})
+	return string(key)
+}
+
+// refreshKey identifies a rule together with its effective refresh schedule.
+func refreshKey(rule config.ObjectRule, cfg *config.Config) string {
+	key, _ := json.Marshal(struct {
+		Watch           string
+		RefreshInterval string
+		RefreshJitter   string
+	}{watchKey(rule, cfg), effective(rule.RefreshInterval, cfg.RefreshInterval), effective(rule.RefreshJitter, cfg.RefreshJitter)})
+	return string(key)
+}
+
+func effective(ruleValue, globalValue string) string {
+	if strings.TrimSpace(ruleValue) != "" {
+		return ruleValue
 	}
+	return globalValue
 }
 
 func (t *Tail) watchRule(ctx context.Context, rule config.ObjectRule) error {
@@ -95,51 +388,200 @@ func (t *Tail) watchRule(ctx context.Context, rule config.ObjectRule) error {
 		return err
 	}
 
+	cfg, _ := t.current()
 	resourceClient := t.Clients.Dynamic.Resource(mapping.Resource)
-	excludedNamespaces := t.Config.ExcludeNamespaces
-	includedNamespaces := discovery.EffectiveNamespaces(rule, t.Config)
+	excludedNamespaces := cfg.ExcludeNamespaces
+	includedNamespaces := discovery.EffectiveNamespaces(rule, cfg)
 
 	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-		if len(includedNamespaces) == 0 {
+		matcher, err := discovery.NewNamespaceMatcher(rule, cfg)
+		if err != nil {
+			return err
+		}
+		if len(includedNamespaces) == 0 && matcher == nil {
 			return t.watchResourceStream(ctx, resourceClient.Namespace(metav1.NamespaceAll), rule, excludedNamespaces)
 		}
-		return t.watchNamespaceSet(ctx, resourceClient, rule, includedNamespaces, excludedNamespaces)
+		return t.watchNamespaceSet(ctx, resourceClient, rule, includedNamespaces, excludedNamespaces, matcher)
 	}
 	return t.watchResourceStream(ctx, resourceClient, rule, nil)
 }
 
-func (t *Tail) watchNamespaceSet(ctx context.Context, client dynamic.NamespaceableResourceInterface, rule config.ObjectRule, includedNamepaces, excludedNamespaces []string) error {
-	errCh := make(chan error, len(includedNamepaces))
-	var wg sync.WaitGroup
-	active := 0
+// watchNamespaceSet runs one watch per namespace. When a matcher is supplied, the set of namespaces follows the
+// Namespace objects whose labels match, starting and stopping watches as namespaces come and go.
+func (t *Tail) watchNamespaceSet(ctx context.Context, client dynamic.NamespaceableResourceInterface, rule config.ObjectRule, includedNamepaces, excludedNamespaces []string, matcher *discovery.NamespaceMatcher) error {
+	watches := newNamespaceWatches(ctx, func(nsCtx context.Context, namespace string) error {
+		return t.watchResourceStream(nsCtx, client.Namespace(namespace), rule, nil)
+	})
+	defer watches.stopAll()
+
+	if matcher != nil {
+		return t.trackNamespaces(ctx, watches, matcher)
+	}
 	for _, ns := range includedNamepaces {
 		if discovery.ShouldExcludeNamespace(ns, excludedNamespaces) {
 			continue
 		}
-		active++
-		nsClient := client.Namespace(ns)
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			if err := t.watchResourceStream(ctx, nsClient, rule, nil); err != nil && !errors.Is(err, context.Canceled) {
-				errCh <- err
-			}
-		}()
+		watches.start(ns)
 	}
-	if active == 0 {
+	return watches.wait()
+}
+
+func (t *Tail) trackNamespaces(ctx context.Context, watches *namespaceWatches, matcher *discovery.NamespaceMatcher) error {
+	client := t.Clients.Dynamic.Resource(discovery.NamespaceResource)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		namespaces, resourceVersion, err := matcher.List(ctx, t.Clients.Dynamic)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		watches.sync(namespaces)
+
+		opts := matcher.ListOptions()
+		opts.ResourceVersion = resourceVersion
+		watcher, err := client.Watch(ctx, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("watch namespaces: %w", err)
+		}
+		if err := consumeNamespaceWatch(ctx, watcher, watches, matcher); err != nil {
+			if errors.Is(err, errWatchClosed) {
+				continue
+			}
+			return err
+		}
 		return nil
 	}
+}
+
+func consumeNamespaceWatch(ctx context.Context, watcher watch.Interface, watches *namespaceWatches, matcher *discovery.NamespaceMatcher) error {
+	result := watcher.ResultChan()
+	for {
+		select {
+		case <-ctx.Done():
+			watcher.Stop()
+			return ctx.Err()
+		case err := <-watches.errCh:
+			watcher.Stop()
+			return err
+		case event, ok := <-result:
+			if !ok {
+				watcher.Stop()
+				return errWatchClosed
+			}
+			// An error event, such as an expired resource version, ends the watch; listing again picks up what it missed.
+			if event.Type == watch.Error {
+				watcher.Stop()
+				return errWatchClosed
+			}
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				if matcher.Matches(obj) {
+					watches.start(obj.GetName())
+				} else {
+					watches.stop(obj.GetName())
+				}
+			case watch.Deleted:
+				watches.stop(obj.GetName())
+			}
+		}
+	}
+}
+
+// namespaceWatches tracks the running per-namespace watches for a single rule. It is not safe for concurrent use;
+// only the goroutine that owns it may start or stop watches.
+type namespaceWatches struct {
+	ctx     context.Context
+	run     func(ctx context.Context, namespace string) error
+	errCh   chan error
+	wg      sync.WaitGroup
+	cancels map[string]context.CancelFunc
+}
+
+func newNamespaceWatches(ctx context.Context, run func(ctx context.Context, namespace string) error) *namespaceWatches {
+	return &namespaceWatches{
+		ctx:     ctx,
+		run:     run,
+		errCh:   make(chan error, 1),
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+func (w *namespaceWatches) start(namespace string) {
+	if _, running := w.cancels[namespace]; running {
+		return
+	}
+	nsCtx, cancel := context.WithCancel(w.ctx)
+	w.cancels[namespace] = cancel
+	w.wg.Add(1)
 	go func() {
-		wg.Wait()
-		close(errCh)
+		defer w.wg.Done()
+		if err := w.run(nsCtx, namespace); err != nil && !errors.Is(err, context.Canceled) {
+			select {
+			case w.errCh <- err:
+			default:
+			}
+		}
 	}()
+}
 
-	for err := range errCh {
-		if err != nil {
-			return err
+func (w *namespaceWatches) stop(namespace string) {
+	if cancel, running := w.cancels[namespace]; running {
+		cancel()
+		delete(w.cancels, namespace)
+	}
+}
+
+// sync starts watches for new namespaces and stops the ones no longer listed.
+func (w *namespaceWatches) sync(namespaces []string) {
+	desired := make(map[string]struct{}, len(namespaces))
+	for _, ns := range namespaces {
+		desired[ns] = struct{}{}
+		w.start(ns)
+	}
+	for ns := range w.cancels {
+		if _, ok := desired[ns]; !ok {
+			w.stop(ns)
 		}
 	}
-	return nil
+}
+
+// stopAll stops every watch and waits for them to finish.
+func (w *namespaceWatches) stopAll() {
+	for ns := range w.cancels {
+		w.stop(ns)
+	}
+	w.wg.Wait()
+}
+
+// wait blocks until every watch has finished, returning the first error reported by any of them.
+func (w *namespaceWatches) wait() error {
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case err := <-w.errCh:
+		return err
+	case <-done:
+	}
+	select {
+	case err := <-w.errCh:
+		return err
+	default:
+		return nil
+	}
 }
 
 var errWatchClosed = errors.New("watch closed")
@@ -149,7 +591,7 @@ func (t *Tail) watchResourceStream(ctx context.Context, client dynamic.ResourceI
 		if err := ctx.Err(); err != nil {
 			return err
 		}
-		watcher, err := client.Watch(ctx, metav1.ListOptions{})
+		watcher, err := client.Watch(ctx, discovery.ListOptions(rule))
 		if err != nil {
 			if ctx.Err() != nil {
 				return ctx.Err()
@@ -185,34 +627,49 @@ func (t *Tail) consumeWatch(ctx context.Context, watcher watch.Interface, rule c
 			if discovery.ShouldExcludeNamespace(obj.GetNamespace(), excludedNamespaces) {
 				continue
 			}
-			switch event.Type {
-			case watch.Added, watch.Modified:
-				diff, err := t.Processor.Process(rule, obj.DeepCopy(), t.Config)
-				if err != nil {
-					watcher.Stop()
-					return fmt.Errorf("process %s %s/%s: %w", rule.Kind, obj.GetNamespace(), obj.GetName(), err)
-				}
-				t.DiffLogger.Log(diff)
-				if t.ManifestLogger != nil {
-					t.ManifestLogger.Log(diff)
-				}
-				t.recordDiffMetrics(ctx, diff)
-			case watch.Deleted:
-				if err := t.Processor.Delete(rule, obj, t.Config); err != nil {
-					watcher.Stop()
-					return fmt.Errorf("delete %s %s/%s: %w", rule.Kind, obj.GetNamespace(), obj.GetName(), err)
-				}
-				diff := &manifest.Diff{Previous: obj}
-				t.DiffLogger.Log(diff)
-				t.recordDiffMetrics(ctx, diff)
-			case watch.Error:
+			if event.Type != watch.Error && !rule.MatchesName(obj.GetName()) {
+				continue
+			}
+			if err := t.handleEvent(ctx, rule, event.Type, obj); err != nil {
 				watcher.Stop()
-				return fmt.Errorf("watch error for %s: %v", rule.Kind, apierrors.FromObject(event.Object))
+				return err
 			}
 		}
 	}
 }
 
+// handleEvent writes or deletes the manifest of an object reported by a watch.
+func (t *Tail) handleEvent(ctx context.Context, rule config.ObjectRule, eventType watch.EventType, obj *unstructured.Unstructured) error {
+	cfg, processor, release := t.acquire()
+	defer release()
+	switch eventType {
+	case watch.Added, watch.Modified:
+		diff, err := processor.Process(rule, obj.DeepCopy(), cfg)
+		if errors.Is(err, manifest.ErrSkipObject) {
+			t.logSkipped(rule, obj, err)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("process %s %s/%s: %w", rule.Kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+		t.DiffLogger.Log(diff)
+		if t.ManifestLogger != nil {
+			t.ManifestLogger.Log(diff)
+		}
+		t.recordDiffMetrics(ctx, diff)
+	case watch.Deleted:
+		if err := processor.Delete(rule, obj, cfg); err != nil {
+			return fmt.Errorf("delete %s %s/%s: %w", rule.Kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+		diff := &manifest.Diff{Previous: obj}
+		t.DiffLogger.Log(diff)
+		t.recordDiffMetrics(ctx, diff)
+	case watch.Error:
+		return fmt.Errorf("watch error for %s: %v", rule.Kind, apierrors.FromObject(obj))
+	}
+	return nil
+}
+
 func (t *Tail) recordDiffMetrics(ctx context.Context, diff *manifest.Diff) {
 	if t.Metrics == nil || diff == nil {
 		return
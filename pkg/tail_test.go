@@ -2,12 +2,17 @@ package pkg
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"sort"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -17,8 +22,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
 
 	"github.com/grafana/k8s-manifest-tail/internal/config"
+	"github.com/grafana/k8s-manifest-tail/internal/discovery"
 	"github.com/grafana/k8s-manifest-tail/internal/kube"
 	"github.com/grafana/k8s-manifest-tail/internal/manifest"
 )
@@ -65,6 +72,44 @@ func TestTailRunFullManifestCheck(t *testing.T) {
 	g.Expect(stubLogger.logged).To(gomega.Equal(1))
 }
 
+func TestTailRunFullManifestCheckSkipsObjectsPatchesDoNotApplyTo(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	api := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", Labels: map[string]string{"team": "edge"}}}
+	worker := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default"}}
+	mapper := newRESTMapper([]resourceMapping{{
+		GVR:   corev1.SchemeGroupVersion.WithResource("pods"),
+		GVK:   corev1.SchemeGroupVersion.WithKind("Pod"),
+		Scope: meta.RESTScopeNamespace,
+	}})
+
+	stubProc := &stubProcessor{}
+	logger := &stubLogger{}
+	tail := Tail{
+		Clients: &kube.Clients{Dynamic: fake.NewSimpleDynamicClient(testScheme, api, worker), Mapper: mapper},
+		Config: &config.Config{
+			Filters: []config.FilterConfig{},
+			Objects: []config.ObjectRule{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Patches:    []config.PatchConfig{{JSON: `[{op: remove, path: /metadata/labels/team}]`}},
+			}},
+		},
+		DiffLogger: &stubDiffLogger{},
+		Processor:  manifest.NewRuleFilters(stubProc, manifest.FilterEnvironment{}),
+		Logger:     logger,
+	}
+
+	total, err := tail.RunFullManifestCheck(context.Background())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(total).To(gomega.Equal(2))
+	g.Expect(stubProc.processed).To(gomega.Equal([]string{"default/api"}))
+	g.Expect(logger.records).To(gomega.HaveLen(1))
+	g.Expect(logger.records[0].Severity()).To(gomega.Equal(log.SeverityWarn))
+	g.Expect(logger.records[0].Body().AsString()).To(gomega.HavePrefix("Skipping Pod default/worker: apply patch 1: "))
+}
+
 func TestTailConsumeWatchHandlesEvents(t *testing.T) {
 	t.Parallel()
 	g := gomega.NewWithT(t)
@@ -109,6 +154,312 @@ func TestTailConsumeWatchHandlesEvents(t *testing.T) {
 	g.Expect(stubLogger.logged).To(gomega.Equal(2))
 }
 
+func TestTailConsumeWatchAppliesNamePatterns(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	stubProc := &stubProcessor{}
+	stubLogger := &stubDiffLogger{}
+	tail := Tail{
+		Config:     &config.Config{},
+		Processor:  stubProc,
+		DiffLogger: stubLogger,
+	}
+	rule := config.ObjectRule{APIVersion: "v1", Kind: "Pod", NamePattern: "^alloy-", ExcludeNamePatterns: []string{"-canary$"}}
+	g.Expect(rule.Validate()).To(gomega.Succeed())
+
+	watcher := watch.NewFake()
+	go func() {
+		for _, name := range []string{"alloy-logs", "alloy-logs-canary", "nodeexporter"} {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("v1")
+			obj.SetKind("Pod")
+			obj.SetNamespace("default")
+			obj.SetName(name)
+			watcher.Add(obj)
+		}
+		watcher.Stop()
+	}()
+
+	err := tail.consumeWatch(context.Background(), watcher, rule, nil)
+	g.Expect(err).To(gomega.Equal(errWatchClosed))
+	g.Expect(stubProc.processed).To(gomega.Equal([]string{"default/alloy-logs"}))
+	g.Expect(stubLogger.logged).To(gomega.Equal(1))
+}
+
+func TestTailWatchResourceStreamPassesSelectors(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dyn := fake.NewSimpleDynamicClient(testScheme)
+	selectors := make(chan string, 1)
+	dyn.PrependWatchReactor("pods", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		restrictions := action.(clienttesting.WatchAction).GetWatchRestrictions()
+		selectors <- restrictions.Labels.String() + " " + restrictions.Fields.String()
+		return true, watch.NewFake(), nil
+	})
+
+	tail := Tail{Config: &config.Config{}}
+	rule := config.ObjectRule{APIVersion: "v1", Kind: "Pod", LabelSelector: "app=api", FieldSelector: "spec.nodeName=node-a"}
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- tail.watchResourceStream(ctx, dyn.Resource(corev1.SchemeGroupVersion.WithResource("pods")).Namespace("default"), rule, nil)
+	}()
+
+	g.Eventually(selectors).Should(gomega.Receive(gomega.Equal("app=api spec.nodeName=node-a")))
+	cancel()
+	g.Eventually(errCh).Should(gomega.Receive(gomega.MatchError(context.Canceled)))
+}
+
+func TestTailWatchNamespaceSetFollowsNamespaceSelector(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	tenantA := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: map[string]string{"tenant": "true"}}}
+	system := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}}
+	dyn := fake.NewSimpleDynamicClient(testScheme, tenantA, system)
+
+	namespaceWatcher := watch.NewFake()
+	dyn.PrependWatchReactor("namespaces", func(clienttesting.Action) (bool, watch.Interface, error) {
+		return true, namespaceWatcher, nil
+	})
+	var mu sync.Mutex
+	podWatchers := map[string]*watch.FakeWatcher{}
+	dyn.PrependWatchReactor("pods", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		podWatcher := watch.NewFake()
+		podWatchers[action.GetNamespace()] = podWatcher
+		return true, podWatcher, nil
+	})
+	watchedNamespaces := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		var names []string
+		for ns, podWatcher := range podWatchers {
+			if !podWatcher.IsStopped() {
+				names = append(names, ns)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	cfg := &config.Config{NamespaceSelector: "tenant=true"}
+	rule := config.ObjectRule{APIVersion: "v1", Kind: "Pod"}
+	matcher, err := discovery.NewNamespaceMatcher(rule, cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	tail := Tail{Clients: &kube.Clients{Dynamic: dyn}, Config: cfg}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- tail.watchNamespaceSet(ctx, dyn.Resource(corev1.SchemeGroupVersion.WithResource("pods")), rule, nil, nil, matcher)
+	}()
+	g.Eventually(watchedNamespaces).Should(gomega.Equal([]string{"tenant-a"}))
+
+	tenantB := namespaceObject("tenant-b", map[string]string{"tenant": "true"})
+	namespaceWatcher.Add(tenantB)
+	g.Eventually(watchedNamespaces).Should(gomega.Equal([]string{"tenant-a", "tenant-b"}))
+
+	namespaceWatcher.Modify(namespaceObject("tenant-a", nil))
+	g.Eventually(watchedNamespaces).Should(gomega.Equal([]string{"tenant-b"}))
+
+	namespaceWatcher.Delete(tenantB)
+	g.Eventually(watchedNamespaces).Should(gomega.BeEmpty())
+
+	cancel()
+	g.Eventually(errCh).Should(gomega.Receive(gomega.MatchError(context.Canceled)))
+}
+
+func TestTailWatchNamespaceSetRelistsAfterWatchErrors(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	tenantA := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: map[string]string{"tenant": "true"}}}
+	dyn := fake.NewSimpleDynamicClient(testScheme, tenantA)
+
+	var mu sync.Mutex
+	var namespaceWatchers []*watch.FakeWatcher
+	dyn.PrependWatchReactor("namespaces", func(clienttesting.Action) (bool, watch.Interface, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		namespaceWatcher := watch.NewFake()
+		namespaceWatchers = append(namespaceWatchers, namespaceWatcher)
+		return true, namespaceWatcher, nil
+	})
+	latestNamespaceWatcher := func() (*watch.FakeWatcher, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(namespaceWatchers) == 0 {
+			return nil, 0
+		}
+		return namespaceWatchers[len(namespaceWatchers)-1], len(namespaceWatchers)
+	}
+	dyn.PrependWatchReactor("pods", func(clienttesting.Action) (bool, watch.Interface, error) {
+		return true, watch.NewFake(), nil
+	})
+
+	cfg := &config.Config{NamespaceSelector: "tenant=true"}
+	rule := config.ObjectRule{APIVersion: "v1", Kind: "Pod"}
+	matcher, err := discovery.NewNamespaceMatcher(rule, cfg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	tail := Tail{Clients: &kube.Clients{Dynamic: dyn}, Config: cfg}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- tail.watchNamespaceSet(ctx, dyn.Resource(corev1.SchemeGroupVersion.WithResource("pods")), rule, nil, nil, matcher)
+	}()
+	g.Eventually(func() int { _, count := latestNamespaceWatcher(); return count }).Should(gomega.Equal(1))
+
+	first, _ := latestNamespaceWatcher()
+	first.Error(&metav1.Status{Status: metav1.StatusFailure, Code: 410, Reason: metav1.StatusReasonExpired})
+	g.Eventually(func() int { _, count := latestNamespaceWatcher(); return count }).Should(gomega.Equal(2))
+	g.Consistently(errCh, 100*time.Millisecond).ShouldNot(gomega.Receive())
+
+	cancel()
+	g.Eventually(errCh).Should(gomega.Receive(gomega.MatchError(context.Canceled)))
+}
+
+func TestNamespaceWatchesStopAllWaitsForWatches(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	var mu sync.Mutex
+	var finished []string
+	watches := newNamespaceWatches(context.Background(), func(ctx context.Context, namespace string) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		finished = append(finished, namespace)
+		return ctx.Err()
+	})
+	watches.start("tenant-a")
+	watches.start("tenant-b")
+
+	watches.stopAll()
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Strings(finished)
+	g.Expect(finished).To(gomega.Equal([]string{"tenant-a", "tenant-b"}))
+}
+
+func namespaceObject(name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Namespace")
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestTailReloadRestartsOnlyChangedRules(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dyn := fake.NewSimpleDynamicClient(testScheme)
+	var mu sync.Mutex
+	started := map[string]int{}
+	watchers := map[string]*watch.FakeWatcher{}
+	for _, resource := range []string{"pods", "services"} {
+		dyn.PrependWatchReactor(resource, func(action clienttesting.Action) (bool, watch.Interface, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			w := watch.NewFake()
+			started[resource]++
+			watchers[resource] = w
+			return true, w, nil
+		})
+	}
+	status := func() map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+		result := map[string]string{}
+		for resource, w := range watchers {
+			state := "running"
+			if w.IsStopped() {
+				state = "stopped"
+			}
+			result[resource] = fmt.Sprintf("%s/%d", state, started[resource])
+		}
+		return result
+	}
+	mapper := newRESTMapper([]resourceMapping{
+		{GVR: corev1.SchemeGroupVersion.WithResource("pods"), GVK: corev1.SchemeGroupVersion.WithKind("Pod"), Scope: meta.RESTScopeNamespace},
+		{GVR: corev1.SchemeGroupVersion.WithResource("services"), GVK: corev1.SchemeGroupVersion.WithKind("Service"), Scope: meta.RESTScopeNamespace},
+	})
+
+	pods := config.ObjectRule{APIVersion: "v1", Kind: "Pod"}
+	services := config.ObjectRule{APIVersion: "v1", Kind: "Service"}
+	tail := &Tail{
+		Clients:    &kube.Clients{Dynamic: dyn, Mapper: mapper},
+		Config:     &config.Config{Objects: []config.ObjectRule{pods}},
+		Processor:  &stubProcessor{},
+		DiffLogger: &stubDiffLogger{},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() { errCh <- tail.WatchResources(ctx) }()
+	g.Eventually(status).Should(gomega.Equal(map[string]string{"pods": "running/1"}))
+
+	tail.Reload(&config.Config{Objects: []config.ObjectRule{pods, services}}, &stubProcessor{})
+	g.Eventually(status).Should(gomega.Equal(map[string]string{"pods": "running/1", "services": "running/1"}))
+
+	changedServices := services
+	changedServices.LabelSelector = "app=api"
+	tail.Reload(&config.Config{Objects: []config.ObjectRule{changedServices}}, &stubProcessor{})
+	g.Eventually(status).Should(gomega.Equal(map[string]string{"pods": "stopped/1", "services": "running/2"}))
+
+	cancel()
+	g.Eventually(errCh).Should(gomega.Receive(gomega.MatchError(context.Canceled)))
+}
+
+func TestRuleTasksWaitForCancelledTasks(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	var mu sync.Mutex
+	running := map[string]int{}
+	overlapped := false
+	tasks := newRuleTasks(context.Background(), func(rule config.ObjectRule, _ *config.Config) string {
+		return rule.Kind + "/" + rule.LabelSelector
+	}, func(ctx context.Context, rule config.ObjectRule) error {
+		mu.Lock()
+		running[rule.Kind]++
+		overlapped = overlapped || running[rule.Kind] > 1
+		mu.Unlock()
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		running[rule.Kind]--
+		mu.Unlock()
+		return ctx.Err()
+	})
+	runningTasks := func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		return maps.Clone(running)
+	}
+
+	tasks.sync(&config.Config{Objects: []config.ObjectRule{{Kind: "Pod"}}})
+	g.Eventually(runningTasks).Should(gomega.Equal(map[string]int{"Pod": 1}))
+
+	tasks.sync(&config.Config{Objects: []config.ObjectRule{{Kind: "Pod", LabelSelector: "app=api"}}})
+	g.Eventually(runningTasks).Should(gomega.Equal(map[string]int{"Pod": 1}))
+
+	tasks.stopAll()
+	g.Expect(runningTasks()).To(gomega.Equal(map[string]int{"Pod": 0}))
+	mu.Lock()
+	defer mu.Unlock()
+	g.Expect(overlapped).To(gomega.BeFalse())
+}
+
 func TestTailRecordDiffMetrics(t *testing.T) {
 	t.Parallel()
 	g := gomega.NewWithT(t)
@@ -131,6 +482,100 @@ func TestTailRecordDiffMetrics(t *testing.T) {
 	g.Expect(metrics.removed).To(gomega.Equal(1))
 }
 
+func TestTailRunScheduledRefreshesUsesRuleSchedules(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	dyn := fake.NewSimpleDynamicClient(testScheme)
+	var mu sync.Mutex
+	lists := map[string]int{}
+	for _, resource := range []string{"pods", "services"} {
+		dyn.PrependReactor("list", resource, func(clienttesting.Action) (bool, runtime.Object, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			lists[resource]++
+			return false, nil, nil
+		})
+	}
+	listed := func(resource string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return lists[resource]
+	}
+	mapper := newRESTMapper([]resourceMapping{
+		{GVR: corev1.SchemeGroupVersion.WithResource("pods"), GVK: corev1.SchemeGroupVersion.WithKind("Pod"), Scope: meta.RESTScopeNamespace},
+		{GVR: corev1.SchemeGroupVersion.WithResource("services"), GVK: corev1.SchemeGroupVersion.WithKind("Service"), Scope: meta.RESTScopeNamespace},
+	})
+
+	metrics := &stubMetrics{}
+	tail := &Tail{
+		Clients: &kube.Clients{Dynamic: dyn, Mapper: mapper},
+		Config: &config.Config{
+			RefreshInterval: "1h",
+			Objects: []config.ObjectRule{
+				{APIVersion: "v1", Kind: "Pod", RefreshInterval: "10ms", RefreshJitter: "5ms"},
+				{APIVersion: "v1", Kind: "Service", RefreshInterval: "never"},
+			},
+		},
+		Processor:  &stubProcessor{},
+		DiffLogger: &stubDiffLogger{},
+		Metrics:    metrics,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() { errCh <- tail.RunScheduledRefreshes(ctx) }()
+
+	g.Eventually(func() int { return listed("pods") }).Should(gomega.BeNumerically(">=", 3))
+	g.Consistently(func() int { return listed("services") }, 50*time.Millisecond).Should(gomega.BeZero())
+
+	tail.Reload(&config.Config{
+		RefreshInterval: "10ms",
+		Objects: []config.ObjectRule{
+			{APIVersion: "v1", Kind: "Service"},
+		},
+	}, &stubProcessor{})
+	g.Eventually(func() int { return listed("services") }).Should(gomega.BeNumerically(">=", 2))
+
+	cancel()
+	g.Eventually(errCh).Should(gomega.Receive(gomega.MatchError(context.Canceled)))
+}
+
+func TestTailRefreshSkipsRuleAlreadyRefreshing(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}}
+	mapper := newRESTMapper([]resourceMapping{{
+		GVR:   corev1.SchemeGroupVersion.WithResource("pods"),
+		GVK:   corev1.SchemeGroupVersion.WithKind("Pod"),
+		Scope: meta.RESTScopeNamespace,
+	}})
+	rule := config.ObjectRule{APIVersion: "v1", Kind: "Pod", RefreshInterval: "1h"}
+	cfg := &config.Config{Objects: []config.ObjectRule{rule}}
+	stubProc := &stubProcessor{}
+	tail := Tail{
+		Clients:    &kube.Clients{Dynamic: fake.NewSimpleDynamicClient(testScheme, pod), Mapper: mapper},
+		Config:     cfg,
+		DiffLogger: &stubDiffLogger{},
+		Processor:  stubProc,
+	}
+
+	// A refresh started under a different schedule is still the same rule.
+	changed := rule
+	changed.RefreshInterval = "5m"
+	g.Expect(tail.beginRefresh(watchKey(changed, cfg))).To(gomega.BeTrue())
+	total, err := tail.RunFullManifestCheck(context.Background())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(total).To(gomega.BeZero())
+	g.Expect(stubProc.processed).To(gomega.BeEmpty())
+
+	tail.endRefresh(watchKey(changed, cfg))
+	total, err = tail.RunFullManifestCheck(context.Background())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(total).To(gomega.Equal(1))
+}
+
 type stubProcessor struct {
 	processed []string
 	deleted   []string
@@ -146,6 +591,19 @@ func (s *stubProcessor) Delete(_ config.ObjectRule, obj *unstructured.Unstructur
 	return nil
 }
 
+type stubLogger struct {
+	embedded.Logger
+	records []log.Record
+}
+
+func (s *stubLogger) Emit(_ context.Context, record log.Record) {
+	s.records = append(s.records, record)
+}
+
+func (s *stubLogger) Enabled(context.Context, log.EnabledParameters) bool {
+	return true
+}
+
 type stubDiffLogger struct {
 	logged int
 }
@@ -199,3 +657,49 @@ func newRESTMapper(mappings []resourceMapping) meta.RESTMapper {
 	}
 	return mapper
 }
+
+func TestTailReloadClosesReplacedProcessorOnceUnused(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	previous := &closingProcessor{}
+	tail := &Tail{Config: &config.Config{}, Processor: previous}
+
+	_, processor, release := tail.acquire()
+	g.Expect(processor).To(gomega.BeIdenticalTo(previous))
+
+	tail.Reload(&config.Config{}, previous)
+	tail.Reload(&config.Config{}, &stubProcessor{})
+	g.Consistently(previous.isClosed, 50*time.Millisecond).Should(gomega.BeFalse())
+	_, err := processor.Process(config.ObjectRule{}, &unstructured.Unstructured{}, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	release()
+	g.Eventually(previous.isClosed).Should(gomega.BeTrue())
+}
+
+type closingProcessor struct {
+	stubProcessor
+	mu     sync.Mutex
+	closed bool
+}
+
+func (c *closingProcessor) Process(rule config.ObjectRule, obj *unstructured.Unstructured, cfg *config.Config) (*manifest.Diff, error) {
+	if c.isClosed() {
+		return nil, errors.New("processor is closed")
+	}
+	return c.stubProcessor.Process(rule, obj, cfg)
+}
+
+func (c *closingProcessor) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *closingProcessor) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
@@ -38,5 +38,10 @@ var _ = Describe("Describe command", func() {
 		Entry("excluded namespaces documented", "exclude_namespaces.yaml",
 			`Pods in all namespaces except "kube-system" or "observability"`,
 		),
+		Entry("effective filter chains listed", "filters.yaml",
+			"Pods in all namespaces\n    Filters: removeStatus -> removeMetadataFields (\"managedFields\", \"resourceVersion\")\n",
+			"HorizontalPodAutoscalers in all namespaces\n    Filters: removeMetadataFields\n",
+			"ConfigMaps in all namespaces\n    Filters: none\n",
+		),
 	)
 })
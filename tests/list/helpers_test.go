@@ -12,10 +12,13 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	discoveryfake "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/dynamic/fake"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
 )
 
 var testScheme = runtime.NewScheme()
@@ -43,12 +46,34 @@ func newFakeProvider(objects []runtime.Object, mappings []resourceMapping) kube.
 	mapper := newRESTMapper(mappings)
 	return &staticProvider{
 		clients: &kube.Clients{
-			Dynamic: dynamicClient,
-			Mapper:  mapper,
+			Dynamic:   dynamicClient,
+			Mapper:    mapper,
+			Discovery: newFakeDiscovery(mappings),
 		},
 	}
 }
 
+func newFakeDiscovery(mappings []resourceMapping) *discoveryfake.FakeDiscovery {
+	byGroupVersion := make(map[string]*metav1.APIResourceList)
+	var lists []*metav1.APIResourceList
+	for _, m := range mappings {
+		groupVersion := m.GVR.GroupVersion().String()
+		list, ok := byGroupVersion[groupVersion]
+		if !ok {
+			list = &metav1.APIResourceList{GroupVersion: groupVersion}
+			byGroupVersion[groupVersion] = list
+			lists = append(lists, list)
+		}
+		list.APIResources = append(list.APIResources, metav1.APIResource{
+			Name:       m.GVR.Resource,
+			Kind:       m.GVK.Kind,
+			Namespaced: m.Scope.Name() == meta.RESTScopeNameNamespace,
+			Verbs:      metav1.Verbs{"get", "list", "watch"},
+		})
+	}
+	return &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: lists}}
+}
+
 func newRESTMapper(mappings []resourceMapping) meta.RESTMapper {
 	groupVersions := make(map[schema.GroupVersion]struct{})
 	for _, m := range mappings {
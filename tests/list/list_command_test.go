@@ -55,6 +55,44 @@ objects:
 		Expect(stdout).To(ContainSubstring("api-prod"))
 	})
 
+	It("merges repeated config files", func() {
+		baseConfig := writeConfigFile(GinkgoT(), `
+namespaces: ["default"]
+objects:
+  - apiVersion: v1
+    kind: Pod
+`)
+		teamConfig := writeConfigFile(GinkgoT(), `
+objects:
+  - apiVersion: apps/v1
+    kind: Deployment
+`)
+		provider := newFakeProvider(
+			[]runtime.Object{
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}},
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+			},
+			[]resourceMapping{
+				{
+					GVR:   corev1.SchemeGroupVersion.WithResource("pods"),
+					GVK:   corev1.SchemeGroupVersion.WithKind("Pod"),
+					Scope: meta.RESTScopeNamespace,
+				},
+				{
+					GVR:   appsv1.SchemeGroupVersion.WithResource("deployments"),
+					GVK:   appsv1.SchemeGroupVersion.WithKind("Deployment"),
+					Scope: meta.RESTScopeNamespace,
+				},
+			},
+		)
+		cmd.SetKubeProvider(provider)
+
+		stdout, stderr, err := runListCommand(baseConfig, "--config", teamConfig)
+		Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("stderr: %s", stderr))
+		Expect(stdout).To(ContainSubstring("api"))
+		Expect(stdout).To(ContainSubstring("web"))
+	})
+
 	It("respects global namespace filters", func() {
 		configPath := writeConfigFile(GinkgoT(), `
 namespaces: ["default"]
@@ -162,4 +200,45 @@ objects:
 		Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("stderr: %s", stderr))
 		Expect(stdout).To(ContainSubstring("No resources found"))
 	})
+
+	It("expands wildcard rules through API discovery", func() {
+		configPath := writeConfigFile(GinkgoT(), `
+objects:
+  - apiVersion: apps/*
+    kind: "*"
+    excludeKinds: [StatefulSet]
+`)
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "default"}}
+		daemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"}}
+		statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}}
+		provider := newFakeProvider(
+			[]runtime.Object{deployment, daemonSet, statefulSet},
+			[]resourceMapping{
+				{
+					GVR:   appsv1.SchemeGroupVersion.WithResource("deployments"),
+					GVK:   appsv1.SchemeGroupVersion.WithKind("Deployment"),
+					Scope: meta.RESTScopeNamespace,
+				},
+				{
+					GVR:   appsv1.SchemeGroupVersion.WithResource("daemonsets"),
+					GVK:   appsv1.SchemeGroupVersion.WithKind("DaemonSet"),
+					Scope: meta.RESTScopeNamespace,
+				},
+				{
+					GVR:   appsv1.SchemeGroupVersion.WithResource("statefulsets"),
+					GVK:   appsv1.SchemeGroupVersion.WithKind("StatefulSet"),
+					Scope: meta.RESTScopeNamespace,
+				},
+			},
+		)
+		cmd.SetKubeProvider(provider)
+
+		stdout, stderr, err := runListCommand(configPath)
+		Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("stderr: %s", stderr))
+		Expect(stdout).To(ContainSubstring("DaemonSet"))
+		Expect(stdout).To(ContainSubstring("agent"))
+		Expect(stdout).To(ContainSubstring("Deployment"))
+		Expect(stdout).To(ContainSubstring("frontend"))
+		Expect(stdout).NotTo(ContainSubstring("StatefulSet"))
+	})
 })